@@ -0,0 +1,467 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package artifact implements a build-cache-style store of files keyed by the content hash
+// of their inputs, on top of the LRU cache. Artifacts are kept on disk, verified every time
+// they are read, and evicted in least recently used order once their total size exceeds a budget.
+package artifact
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"cachito/lru"
+)
+
+// tempPrefix names the files being produced, which are not artifacts yet
+const tempPrefix = ".tmp-"
+
+// digestSuffix names the sidecar files holding the digest of each artifact,
+// written when it is produced and checked when the store is opened again
+const digestSuffix = ".sha256"
+
+// ErrInvalidHash is returned for the hashes that cannot name a file, like empty ones
+// or the ones containing path separators.
+var ErrInvalidHash = errors.New("invalid artifact hash")
+
+// ErrTooLarge is returned for the artifacts larger than the store, which are not kept.
+var ErrTooLarge = errors.New("artifact larger than the store")
+
+// Artifact is a file stored in a Store.
+type Artifact struct {
+	Hash   string // Content hash of the inputs the artifact was produced from
+	Path   string // Location of the file
+	Size   int64  // Size of the file in bytes
+	Digest string // Hex-encoded SHA-256 of the file, checked on every read
+}
+
+// usage tracks the disk used by the artifacts of a store
+type usage struct {
+	maxBytes int64
+	bytes    int64
+}
+
+// Store keeps artifacts on disk, in a single directory, up to a maximum total size.
+// Each artifact is stored next to a sidecar file holding its digest.
+// It is safe for concurrent use, but it expects to be the only writer of its directory.
+type Store struct {
+	dir      string
+	maxBytes int64
+	cache    *lru.LRU[string, Artifact, usage]
+
+	mu     sync.Mutex
+	leases map[string]int // Leases of each hash handed out by Acquire and not released yet
+}
+
+// Lease is an artifact handed out by Acquire. Its file is protected from eviction
+// until Release is called.
+type Lease struct {
+	Artifact
+	store *Store
+	once  sync.Once
+}
+
+// Release makes the artifact evictable again, once every lease on it is released.
+// Its file must not be used afterwards. Calling it more than once has no effect.
+func (l *Lease) Release() {
+	l.once.Do(func() { l.store.release(l.Hash) })
+}
+
+// Open creates a store keeping at most maxBytes of artifacts in dir, creating it when needed.
+// Artifacts left in dir by a previous store are indexed again, the most recently
+// modified ones being the most recently used. The ones that do not match their recorded
+// digest, or have none, are removed, along with the leftover temporary files.
+func Open(dir string, maxBytes int64) (*Store, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("cannot create the artifact directory: %w", err)
+	}
+
+	s := &Store{
+		dir:      dir,
+		maxBytes: maxBytes,
+		cache:    lru.New[string, Artifact](usage{maxBytes: maxBytes}),
+		leases:   make(map[string]int),
+	}
+
+	s.cache.SetCostFunc(func(entry lru.Entry[string, Artifact]) int64 { return entry.Value.Size })
+	s.cache.ShouldEvict(func(u *usage, entry, _ lru.Entry[string, Artifact]) bool {
+		return u.bytes+entry.Value.Size > u.maxBytes
+	})
+	s.cache.OnInsert(func(u *usage, entry lru.Entry[string, Artifact]) error {
+		u.bytes += entry.Value.Size
+		return nil
+	})
+	s.cache.OnDelete(func(u *usage, entry lru.Entry[string, Artifact], reason lru.RemovalReason) error {
+		// The new artifact of a replacement has the same hash, so it is stored in the same files
+		if reason != lru.ReasonReplaced {
+			if err := removeArtifact(entry.Value.Path); err != nil {
+				return fmt.Errorf("cannot remove artifact %s: %w", entry.Key, err)
+			}
+		}
+		// Only once the files are gone, as the entry is kept when they cannot be removed
+		u.bytes -= entry.Value.Size
+		return nil
+	})
+
+	if err := s.reindex(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// reindex inserts the artifacts already present in the directory whose file matches their digest
+func (s *Store) reindex() error {
+	dirEntries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return fmt.Errorf("cannot read the artifact directory: %w", err)
+	}
+
+	var artifacts []os.FileInfo
+	for _, dirEntry := range dirEntries {
+		name := dirEntry.Name()
+		path := filepath.Join(s.dir, name)
+		switch {
+		case !dirEntry.Type().IsRegular():
+			continue
+		case strings.HasPrefix(name, tempPrefix):
+			_ = os.Remove(path)
+			continue
+		case strings.HasSuffix(name, digestSuffix):
+			// Digests whose artifact is gone are left by interrupted removals
+			if _, err := os.Stat(strings.TrimSuffix(path, digestSuffix)); errors.Is(err, os.ErrNotExist) {
+				_ = os.Remove(path)
+			}
+			continue
+		}
+
+		info, err := dirEntry.Info()
+		if err != nil {
+			return fmt.Errorf("cannot stat artifact %s: %w", name, err)
+		}
+		artifacts = append(artifacts, info)
+	}
+
+	// Oldest first, so the most recently modified artifacts end up as the most recently used
+	slices.SortFunc(artifacts, func(a, b os.FileInfo) int { return a.ModTime().Compare(b.ModTime()) })
+	for _, info := range artifacts {
+		artifact := Artifact{Hash: info.Name(), Path: s.path(info.Name()), Size: info.Size()}
+		recorded, err := os.ReadFile(artifact.Path + digestSuffix)
+		if err == nil {
+			artifact.Digest = strings.TrimSpace(string(recorded))
+			err = verify(artifact)
+		}
+		if err != nil || artifact.Size > s.maxBytes {
+			// Corrupted, interrupted before its digest was written, or too large for the store
+			if err := removeArtifact(artifact.Path); err != nil {
+				return fmt.Errorf("cannot remove artifact %s: %w", artifact.Hash, err)
+			}
+			continue
+		}
+
+		if err := s.cache.CreateElement(artifact.Hash, artifact); err != nil {
+			return fmt.Errorf("cannot index artifact %s: %w", artifact.Hash, err)
+		}
+	}
+	return nil
+}
+
+// GetOrCompute returns the artifact stored for the given hash. When it is missing,
+// or its file does not match the digest recorded when it was produced, producer
+// is called to write it again, once. Concurrent calls for the same hash share a single producer.
+// Artifacts larger than the store fail with ErrTooLarge, and are not kept.
+// The file can be evicted by concurrent calls as soon as it is returned,
+// use Acquire to keep it while it is being used.
+func (s *Store) GetOrCompute(hash string, producer func(w io.Writer) error) (Artifact, error) {
+	return s.getOrCompute(hash, producer, false)
+}
+
+// Acquire behaves like GetOrCompute, but the artifact is leased: it is not evicted
+// until the lease is released, so its file can be used safely meanwhile.
+// Leased artifacts still count in the size of the store, so when they fill it,
+// the artifacts that need to evict others fail with lru.ErrEvictionStalled.
+// They are still removed by Delete.
+func (s *Store) Acquire(hash string, producer func(w io.Writer) error) (*Lease, error) {
+	artifact, err := s.getOrCompute(hash, producer, true)
+	if err != nil {
+		return nil, err
+	}
+	return &Lease{Artifact: artifact, store: s}, nil
+}
+
+// getOrCompute implements GetOrCompute, leasing the returned artifact when lease is true
+func (s *Store) getOrCompute(hash string, producer func(w io.Writer) error, lease bool) (Artifact, error) {
+	if err := validateHash(hash); err != nil {
+		return Artifact{}, err
+	}
+
+	var verifyErr error
+	for range 2 {
+		// Only the caller running the producer knows whether it published the files
+		published := false
+		artifact, err := s.cache.GetOrCreate(hash, func() (Artifact, error) {
+			artifact, err := s.produce(hash, producer)
+			published = err == nil
+			return artifact, err
+		})
+		if err != nil {
+			if published {
+				// The insertion failed, so the published files belong to no entry
+				_ = removeArtifact(s.path(hash))
+			}
+			return Artifact{}, err
+		}
+
+		// Leased before verifying, so the verified file cannot be evicted afterwards
+		if lease {
+			leased, err := s.acquire(hash)
+			if err != nil {
+				return Artifact{}, err
+			}
+			if !leased {
+				// Evicted right after being returned, get it again
+				verifyErr = fmt.Errorf("artifact %s was evicted before being leased: %w", hash, lru.ErrNotFound)
+				continue
+			}
+		}
+		if verifyErr = verify(artifact); verifyErr == nil {
+			return artifact, nil
+		}
+		if lease {
+			s.release(hash)
+		}
+
+		// Corrupted or missing file, produce it again
+		if err := s.cache.DeleteElement(hash); err != nil {
+			return Artifact{}, errors.Join(verifyErr, err)
+		}
+	}
+	return Artifact{}, verifyErr
+}
+
+// Get returns the artifact stored for the given hash, verifying its file.
+// lru.ErrNotFound is returned when it is missing. Corrupted artifacts are removed,
+// and their verification error is returned. Like with GetOrCompute, the file
+// can be evicted by concurrent calls as soon as it is returned.
+func (s *Store) Get(hash string) (Artifact, error) {
+	if err := validateHash(hash); err != nil {
+		return Artifact{}, err
+	}
+
+	artifact, err := s.cache.GetElement(hash)
+	if err != nil {
+		return Artifact{}, err
+	}
+	if err := verify(artifact); err != nil {
+		return Artifact{}, errors.Join(err, s.cache.DeleteElement(hash))
+	}
+	return artifact, nil
+}
+
+// Delete removes the artifact stored for the given hash, if any, along with its file.
+func (s *Store) Delete(hash string) error {
+	if err := validateHash(hash); err != nil {
+		return err
+	}
+	return s.cache.DeleteElement(hash)
+}
+
+// Len returns the amount of artifacts stored.
+func (s *Store) Len() int {
+	return s.cache.Len()
+}
+
+// SizeBytes returns the total size of the artifacts stored.
+func (s *Store) SizeBytes() int64 {
+	return s.cache.SizeBytes()
+}
+
+// acquire leases the artifact stored for the given hash, pinning it in the cache,
+// and reports whether it was found
+func (s *Store) acquire(hash string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Pinned again even when already leased, as the entry may have been removed and produced again
+	if found, err := s.cache.Pin(hash); !found || err != nil {
+		return false, err
+	}
+	s.leases[hash]++
+	return true, nil
+}
+
+// release releases a lease on the artifact stored for the given hash,
+// unpinning it once there are no more
+func (s *Store) release(hash string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.leases[hash]--
+	if s.leases[hash] > 0 {
+		return
+	}
+	delete(s.leases, hash)
+	// It cannot fail, as the store never freezes nor closes its cache
+	_, _ = s.cache.Unpin(hash)
+}
+
+// produce runs a producer into a temporary file, and moves it to the path of the artifact
+// once it succeeded, so a failing or concurrent producer never leaves a partial artifact.
+// The file is synced before it is published, and its digest is recorded next to it,
+// so an artifact truncated by a crash is detected when the store is opened again.
+func (s *Store) produce(hash string, producer func(w io.Writer) error) (Artifact, error) {
+	file, err := os.CreateTemp(s.dir, tempPrefix+"*")
+	if err != nil {
+		return Artifact{}, fmt.Errorf("cannot create artifact %s: %w", hash, err)
+	}
+	defer os.Remove(file.Name()) // No-op once renamed
+
+	hasher := sha256.New()
+	counter := &countingWriter{w: io.MultiWriter(file, hasher)}
+	err = producer(counter)
+	if err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return Artifact{}, fmt.Errorf("cannot produce artifact %s: %w", hash, err)
+	}
+	if counter.n > s.maxBytes {
+		return Artifact{}, fmt.Errorf("%w: %s is %d bytes", ErrTooLarge, hash, counter.n)
+	}
+
+	artifact := Artifact{
+		Hash:   hash,
+		Path:   s.path(hash),
+		Size:   counter.n,
+		Digest: hex.EncodeToString(hasher.Sum(nil)),
+	}
+	if err := os.Rename(file.Name(), artifact.Path); err != nil {
+		return Artifact{}, fmt.Errorf("cannot store artifact %s: %w", hash, err)
+	}
+	if err := s.writeDigest(artifact); err != nil {
+		_ = removeArtifact(artifact.Path)
+		return Artifact{}, fmt.Errorf("cannot store artifact %s: %w", hash, err)
+	}
+	return artifact, nil
+}
+
+// writeDigest records the digest of an artifact in its sidecar file, durably
+func (s *Store) writeDigest(artifact Artifact) error {
+	file, err := os.CreateTemp(s.dir, tempPrefix+"*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name()) // No-op once renamed
+
+	_, err = io.WriteString(file, artifact.Digest+"\n")
+	if err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if err := os.Rename(file.Name(), artifact.Path+digestSuffix); err != nil {
+		return err
+	}
+	return syncDir(s.dir)
+}
+
+// removeArtifact removes the file of an artifact and its digest. The digest goes first,
+// so an interrupted removal leaves an artifact without digest, which is discarded on reopen.
+func removeArtifact(path string) error {
+	for _, name := range []string{path + digestSuffix, path} {
+		if err := os.Remove(name); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+// syncDir flushes a directory, so the renames done in it survive a crash
+func syncDir(dir string) error {
+	file, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return file.Sync()
+}
+
+// path returns the location of the artifact with the given hash
+func (s *Store) path(hash string) string {
+	return filepath.Join(s.dir, hash)
+}
+
+// validateHash checks that a hash can be used as the file name of an artifact
+func validateHash(hash string) error {
+	if hash == "" || hash == "." || hash == ".." || strings.HasPrefix(hash, tempPrefix) ||
+		strings.HasSuffix(hash, digestSuffix) || strings.ContainsAny(hash, `/\`) {
+		return fmt.Errorf("%w: %q", ErrInvalidHash, hash)
+	}
+	return nil
+}
+
+// verify checks that the file of an artifact matches its digest
+func verify(artifact Artifact) error {
+	actual, err := digest(artifact.Path)
+	if err != nil {
+		return err
+	}
+	if actual != artifact.Digest {
+		return fmt.Errorf("artifact %s is corrupted: digest %s, expected %s", artifact.Hash, actual, artifact.Digest)
+	}
+	return nil
+}
+
+// digest returns the hex-encoded SHA-256 of a file
+func digest(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("cannot read artifact: %w", err)
+	}
+	defer file.Close()
+
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, file); err != nil {
+		return "", fmt.Errorf("cannot read artifact: %w", err)
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+// countingWriter counts the bytes written through it
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,247 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package artifact
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"cachito/lru"
+)
+
+// producerOf returns a producer writing size bytes, counting its calls
+func producerOf(size int, calls *int) func(w io.Writer) error {
+	return func(w io.Writer) error {
+		*calls++
+		_, err := io.WriteString(w, strings.Repeat("x", size))
+		return err
+	}
+}
+
+func openStore(t *testing.T, dir string, maxBytes int64) *Store {
+	t.Helper()
+	store, err := Open(dir, maxBytes)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	return store
+}
+
+func TestGetOrComputeProducesOnce(t *testing.T) {
+	store := openStore(t, t.TempDir(), 100)
+
+	calls := 0
+	for range 2 {
+		artifact, err := store.GetOrCompute("h1", producerOf(40, &calls))
+		if err != nil {
+			t.Fatalf("GetOrCompute: %v", err)
+		}
+		if artifact.Size != 40 {
+			t.Fatalf("got size %d, want 40", artifact.Size)
+		}
+	}
+	if calls != 1 {
+		t.Fatalf("producer called %d times, want 1", calls)
+	}
+}
+
+func TestCorruptedArtifactIsProducedAgain(t *testing.T) {
+	store := openStore(t, t.TempDir(), 100)
+
+	calls := 0
+	artifact, err := store.GetOrCompute("h1", producerOf(40, &calls))
+	if err != nil {
+		t.Fatalf("GetOrCompute: %v", err)
+	}
+	if err := os.WriteFile(artifact.Path, []byte("corrupt"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	artifact, err = store.GetOrCompute("h1", producerOf(40, &calls))
+	if err != nil || artifact.Size != 40 {
+		t.Fatalf("GetOrCompute after corruption: got %+v, %v", artifact, err)
+	}
+	if calls != 2 {
+		t.Fatalf("producer called %d times, want 2", calls)
+	}
+}
+
+func TestGetRemovesCorruptedArtifact(t *testing.T) {
+	store := openStore(t, t.TempDir(), 100)
+
+	calls := 0
+	artifact, err := store.GetOrCompute("h1", producerOf(40, &calls))
+	if err != nil {
+		t.Fatalf("GetOrCompute: %v", err)
+	}
+	if err := os.WriteFile(artifact.Path, []byte("corrupt"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := store.Get("h1"); err == nil {
+		t.Fatal("Get returned a corrupted artifact")
+	}
+	if _, err := store.Get("h1"); !errors.Is(err, lru.ErrNotFound) {
+		t.Fatalf("Get after corruption: got %v, want ErrNotFound", err)
+	}
+}
+
+func TestEvictionBySize(t *testing.T) {
+	dir := t.TempDir()
+	store := openStore(t, dir, 100)
+
+	calls := 0
+	for _, hash := range []string{"h1", "h2", "h3"} {
+		if _, err := store.GetOrCompute(hash, producerOf(40, &calls)); err != nil {
+			t.Fatalf("GetOrCompute %s: %v", hash, err)
+		}
+	}
+
+	if store.Len() != 2 || store.SizeBytes() != 80 {
+		t.Fatalf("got %d artifacts of %d bytes, want 2 of 80", store.Len(), store.SizeBytes())
+	}
+	if _, err := os.Stat(filepath.Join(dir, "h1")); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("evicted artifact still on disk: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "h1"+digestSuffix)); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("digest of evicted artifact still on disk: %v", err)
+	}
+
+	if _, err := store.GetOrCompute("big", producerOf(101, &calls)); !errors.Is(err, ErrTooLarge) {
+		t.Fatalf("got %v, want ErrTooLarge", err)
+	}
+	if store.Len() != 2 {
+		t.Fatalf("a too large artifact evicted others, %d left", store.Len())
+	}
+	if _, err := os.Stat(filepath.Join(dir, "big")); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("too large artifact left on disk: %v", err)
+	}
+}
+
+func TestFailedRemovalKeepsAccounting(t *testing.T) {
+	dir := t.TempDir()
+	store := openStore(t, dir, 100)
+
+	calls := 0
+	for _, hash := range []string{"h1", "h2"} {
+		if _, err := store.GetOrCompute(hash, producerOf(40, &calls)); err != nil {
+			t.Fatalf("GetOrCompute %s: %v", hash, err)
+		}
+	}
+
+	// A non-empty directory in place of the artifact makes its removal fail
+	path := filepath.Join(dir, "h1")
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(path, "busy"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Delete("h1"); err == nil {
+		t.Fatal("Delete succeeded without removing the artifact")
+	}
+
+	// h1 still counts, so making room for h3 must evict it, which fails again
+	if _, err := store.GetOrCompute("h3", producerOf(40, &calls)); err == nil {
+		t.Fatal("GetOrCompute exceeded the size of the store")
+	}
+	if store.Len() != 2 {
+		t.Fatalf("got %d artifacts, want 2", store.Len())
+	}
+}
+
+func TestReopen(t *testing.T) {
+	dir := t.TempDir()
+	store := openStore(t, dir, 100)
+
+	calls := 0
+	for _, hash := range []string{"h1", "h2"} {
+		if _, err := store.GetOrCompute(hash, producerOf(40, &calls)); err != nil {
+			t.Fatalf("GetOrCompute %s: %v", hash, err)
+		}
+	}
+
+	// Corrupted while the store was closed, and a leftover of an interrupted producer
+	if err := os.WriteFile(filepath.Join(dir, "h2"), []byte("corrupt"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, tempPrefix+"partial"), []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened := openStore(t, dir, 100)
+	if reopened.Len() != 1 || reopened.SizeBytes() != 40 {
+		t.Fatalf("got %d artifacts of %d bytes, want 1 of 40", reopened.Len(), reopened.SizeBytes())
+	}
+	if _, err := reopened.Get("h1"); err != nil {
+		t.Fatalf("Get h1: %v", err)
+	}
+	if _, err := reopened.Get("h2"); !errors.Is(err, lru.ErrNotFound) {
+		t.Fatalf("corrupted artifact adopted on reopen: %v", err)
+	}
+	for _, name := range []string{"h2", "h2" + digestSuffix, tempPrefix + "partial"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); !errors.Is(err, os.ErrNotExist) {
+			t.Fatalf("%s left on disk after reopen: %v", name, err)
+		}
+	}
+}
+
+func TestInvalidHash(t *testing.T) {
+	store := openStore(t, t.TempDir(), 100)
+
+	calls := 0
+	for _, hash := range []string{"", "..", "../x", tempPrefix + "x", "x" + digestSuffix} {
+		if _, err := store.GetOrCompute(hash, producerOf(1, &calls)); !errors.Is(err, ErrInvalidHash) {
+			t.Fatalf("hash %q: got %v, want ErrInvalidHash", hash, err)
+		}
+	}
+}
+
+func TestLeasedArtifactIsNotEvicted(t *testing.T) {
+	dir := t.TempDir()
+	store := openStore(t, dir, 100)
+
+	calls := 0
+	lease, err := store.Acquire("h1", producerOf(40, &calls))
+	if err != nil {
+		t.Fatalf("Acquire: %v", err)
+	}
+	for _, hash := range []string{"h2", "h3"} {
+		if _, err := store.GetOrCompute(hash, producerOf(40, &calls)); err != nil {
+			t.Fatalf("GetOrCompute %s: %v", hash, err)
+		}
+	}
+	if _, err := os.Stat(lease.Path); err != nil {
+		t.Fatalf("leased artifact evicted: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "h2")); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("got h2 on disk, want it evicted instead of the leased one: %v", err)
+	}
+
+	lease.Release()
+	lease.Release()
+	if _, err := store.GetOrCompute("h4", producerOf(40, &calls)); err != nil {
+		t.Fatalf("GetOrCompute h4: %v", err)
+	}
+	if _, err := os.Stat(lease.Path); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("released artifact still on disk: %v", err)
+	}
+}
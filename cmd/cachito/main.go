@@ -0,0 +1,136 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Cachito is a command line companion for the cachito library.
+//
+// Usage:
+//
+//	cachito replay [-capacity n] [-step] journal.jsonl
+//	cachito history [-limit n] key journal.jsonl
+//	cachito soak [-duration d] [-workers n] [-capacity n] [-keys n] [fault flags]
+//
+// The replay command applies a journal written by LRU.SetJournal to a fresh cache
+// with string keys, printing the operation and the resulting keys after each record.
+// With -step, it waits for Enter before applying the next record.
+//
+// The history command prints the lifecycle of a single key recorded in a journal:
+// when it was inserted, refreshed and read, and why it was removed.
+//
+// The soak command runs a random workload against a cache injecting faults with
+// LRU.SetFaultInjection, and fails when an operation returns an unexpected error
+// or the cache grows over its capacity. Run it with -h to list the fault flags.
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"cachito/lru"
+)
+
+func main() {
+	log.SetFlags(0)
+
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	switch os.Args[1] {
+	case "replay":
+		replay(os.Args[2:])
+	case "history":
+		history(os.Args[2:])
+	case "soak":
+		soak(os.Args[2:])
+	default:
+		usage()
+	}
+}
+
+// usage prints the available commands and exits
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: cachito replay [-capacity n] [-step] journal.jsonl")
+	fmt.Fprintln(os.Stderr, "       cachito history [-limit n] key journal.jsonl")
+	fmt.Fprintln(os.Stderr, "       cachito soak [-duration d] [-workers n] [-capacity n] [-keys n] [fault flags]")
+	os.Exit(2)
+}
+
+// replay runs the replay command
+func replay(args []string) {
+	flags := flag.NewFlagSet("replay", flag.ExitOnError)
+	capacity := flags.Int("capacity", 0, "capacity of the replayed cache, zero means unbounded")
+	step := flags.Bool("step", false, "wait for Enter before applying each record")
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		usage()
+	}
+
+	file, err := os.Open(flags.Arg(0))
+	if err != nil {
+		log.Fatalf("cannot open the journal: %v", err)
+	}
+	defer file.Close()
+
+	// Values are kept raw, only the keys and their order are shown
+	cache := lru.NewWithCapacity[string, json.RawMessage](*capacity, struct{}{})
+	stdin := bufio.NewReader(os.Stdin)
+
+	applied, err := cache.Replay(file, func(record lru.JournalRecord[string, json.RawMessage]) error {
+		fmt.Printf("#%d %s %q -> %q\n", record.Seq, record.Op, record.Key, cache.Keys())
+		if *step {
+			_, err := stdin.ReadString('\n')
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		log.Fatalf("replay stopped after %d records: %v", applied, err)
+	}
+	fmt.Printf("replayed %d records\n", applied)
+}
+
+// history runs the history command
+func history(args []string) {
+	flags := flag.NewFlagSet("history", flag.ExitOnError)
+	limit := flags.Int("limit", 0, "amount of recent events shown, zero means all of them")
+	flags.Parse(args)
+
+	if flags.NArg() != 2 {
+		usage()
+	}
+
+	file, err := os.Open(flags.Arg(1))
+	if err != nil {
+		log.Fatalf("cannot open the journal: %v", err)
+	}
+	defer file.Close()
+
+	events, err := lru.KeyHistory(file, flags.Arg(0), *limit)
+	for _, event := range events {
+		fmt.Printf("#%d %s\n", event.Seq, event)
+	}
+	if err != nil {
+		log.Fatalf("cannot read the journal: %v", err)
+	}
+	if len(events) == 0 {
+		fmt.Printf("key %q not found in the journal\n", flags.Arg(0))
+	}
+}
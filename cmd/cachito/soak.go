@@ -0,0 +1,126 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"cachito/lru"
+)
+
+// soakResult counts the outcomes of the operations run by the soak command
+type soakResult struct {
+	operations atomic.Uint64
+	injected   atomic.Uint64
+	unexpected atomic.Uint64
+}
+
+// soak runs the soak command
+func soak(args []string) {
+	flags := flag.NewFlagSet("soak", flag.ExitOnError)
+	duration := flags.Duration("duration", 10*time.Second, "how long the workload runs")
+	workers := flags.Int("workers", 8, "amount of goroutines running operations")
+	capacity := flags.Int("capacity", 1000, "capacity of the cache")
+	keys := flags.Int("keys", 5000, "amount of distinct keys used by the workload")
+	handlerErrors := flags.Float64("handler-errors", 0.01, "fraction of handler calls failing")
+	lockDelays := flags.Float64("lock-delays", 0.001, "fraction of lock acquisitions delayed")
+	lockDelay := flags.Duration("lock-delay", time.Millisecond, "maximum injected lock delay")
+	asyncDrops := flags.Float64("async-drops", 0, "fraction of asynchronous handler calls dropped, enables async handlers")
+	flags.Parse(args)
+
+	if flags.NArg() != 0 || *capacity <= 0 || *keys <= 0 {
+		usage()
+	}
+
+	cache := lru.NewWithCapacity[string, int](*capacity, struct{}{})
+	cache.OnInsert(func(*struct{}, lru.Entry[string, int]) error { return nil })
+	cache.OnDelete(func(*struct{}, lru.Entry[string, int], lru.RemovalReason) error { return nil })
+	cache.OnAccess(func(*struct{}, lru.Entry[string, int]) error { return nil })
+	if *asyncDrops > 0 {
+		cache.SetAsyncHandlers(lru.AsyncHandlers{Workers: 2, QueueSize: 1024, DropWhenFull: true})
+		defer cache.StopAsyncHandlers()
+	}
+	cache.SetFaultInjection(&lru.FaultInjection{
+		HandlerErrorRate: *handlerErrors,
+		LockDelayRate:    *lockDelays,
+		LockDelay:        *lockDelay,
+		AsyncDropRate:    *asyncDrops,
+	})
+
+	var result soakResult
+	deadline := time.Now().Add(*duration)
+
+	var wg sync.WaitGroup
+	for range max(*workers, 1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for time.Now().Before(deadline) {
+				soakOperation(cache, strconv.Itoa(rand.IntN(*keys)), &result)
+			}
+		}()
+	}
+	wg.Wait()
+
+	stats := cache.Stats()
+	fmt.Printf("operations: %d\n", result.operations.Load())
+	fmt.Printf("injected failures: %d\n", result.injected.Load())
+	fmt.Printf("unexpected errors: %d\n", result.unexpected.Load())
+	fmt.Printf("evictions: %d, dropped handler calls: %d, lock contentions: %d\n",
+		stats.Evictions, stats.DroppedHandlerCalls, stats.LockContentions)
+
+	if cache.Len() > *capacity {
+		log.Fatalf("the cache holds %d entries over its capacity of %d", cache.Len(), *capacity)
+	}
+	if result.unexpected.Load() > 0 {
+		os.Exit(1)
+	}
+}
+
+// soakOperation runs a random operation on the given key and records its outcome
+func soakOperation(cache *lru.LRU[string, int, struct{}], key string, result *soakResult) {
+	var err error
+	switch rand.IntN(4) {
+	case 0:
+		_, err = cache.GetElement(key)
+	case 1:
+		_, err = cache.GetOrCreate(key, func() (int, error) { return len(key), nil })
+	case 2:
+		err = cache.CreateElement(key, len(key))
+	case 3:
+		err = cache.DeleteElement(key)
+	}
+	result.operations.Add(1)
+
+	switch {
+	case err == nil, errors.Is(err, lru.ErrNotFound):
+	case errors.Is(err, lru.ErrInjectedFault):
+		result.injected.Add(1)
+	default:
+		result.unexpected.Add(1)
+		log.Printf("unexpected error on key %s: %v", key, err)
+	}
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Cachitogen generates strongly-typed façades over the generic LRU cache,
+// so large codebases can use named cache types with domain-specific signatures.
+//
+// Usage:
+//
+//	//go:generate cachitogen -type UserCache -key int64 -value *User -output user_cache.go
+package main
+
+import (
+	"bytes"
+	"flag"
+	"go/format"
+	"log"
+	"os"
+	"strings"
+	"text/template"
+)
+
+// facadeTemplate is the source of the generated façade
+var facadeTemplate = template.Must(template.New("facade").Parse(`// Code generated by cachitogen. DO NOT EDIT.
+
+package {{ .Package }}
+
+import (
+	"time"
+
+	lru "{{ .LRUImport }}"
+{{- range .Imports }}
+	"{{ . }}"
+{{- end }}
+)
+
+// {{ .Type }} is a cache of {{ .Value }} values indexed by {{ .Key }} keys.
+type {{ .Type }} struct {
+	cache *lru.LRU[{{ .Key }}, {{ .Value }}, {{ .Metadata }}]
+}
+
+// New{{ .Type }} creates a {{ .Type }} that keeps at most capacity entries.
+// A capacity of zero means it is unbounded.
+func New{{ .Type }}(capacity int, metadata {{ .Metadata }}) *{{ .Type }} {
+	return &{{ .Type }}{
+		cache: lru.NewWithCapacity[{{ .Key }}, {{ .Value }}](capacity, metadata),
+	}
+}
+
+// Get returns the value associated with the given key, or lru.ErrNotFound on a miss.
+func (c *{{ .Type }}) Get(key {{ .Key }}) ({{ .Value }}, error) {
+	return c.cache.GetElement(key)
+}
+
+// Peek returns the value associated with the given key, and whether it was found,
+// without updating its recency.
+func (c *{{ .Type }}) Peek(key {{ .Key }}) ({{ .Value }}, bool) {
+	return c.cache.Peek(key)
+}
+
+// Set inserts or updates an entry.
+func (c *{{ .Type }}) Set(key {{ .Key }}, value {{ .Value }}) error {
+	return c.cache.CreateElement(key, value)
+}
+
+// SetWithTTL inserts or updates an entry that expires after ttl.
+func (c *{{ .Type }}) SetWithTTL(key {{ .Key }}, value {{ .Value }}, ttl time.Duration) error {
+	return c.cache.CreateElementWithTTL(key, value, ttl)
+}
+
+// Delete removes an entry by key.
+func (c *{{ .Type }}) Delete(key {{ .Key }}) error {
+	return c.cache.DeleteElement(key)
+}
+
+// GetOrLoad returns the value associated with the given key, calling the loader when missing.
+func (c *{{ .Type }}) GetOrLoad(key {{ .Key }}, loader func() ({{ .Value }}, error)) ({{ .Value }}, error) {
+	return c.cache.GetOrCreate(key, loader)
+}
+
+// Cache returns the underlying cache, to define handlers or use advanced features.
+func (c *{{ .Type }}) Cache() *lru.LRU[{{ .Key }}, {{ .Value }}, {{ .Metadata }}] {
+	return c.cache
+}
+`))
+
+// facade holds the parameters of the generated façade
+type facade struct {
+	Package   string
+	Type      string
+	Key       string
+	Value     string
+	Metadata  string
+	LRUImport string
+	Imports   []string
+}
+
+func main() {
+	var f facade
+	var imports, output string
+
+	flag.StringVar(&f.Package, "package", os.Getenv("GOPACKAGE"), "package of the generated file (defaults to $GOPACKAGE)")
+	flag.StringVar(&f.Type, "type", "", "name of the generated type")
+	flag.StringVar(&f.Key, "key", "string", "type of the keys")
+	flag.StringVar(&f.Value, "value", "", "type of the values")
+	flag.StringVar(&f.Metadata, "metadata", "struct{}", "type of the cache metadata")
+	flag.StringVar(&f.LRUImport, "lru-import", "cachito/lru", "import path of the lru package")
+	flag.StringVar(&imports, "imports", "", "comma-separated extra import paths needed by the key, value or metadata types")
+	flag.StringVar(&output, "output", "", "output file (defaults to stdout)")
+	flag.Parse()
+
+	if f.Package == "" || f.Type == "" || f.Value == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	for _, path := range strings.Split(imports, ",") {
+		if path = strings.TrimSpace(path); path != "" {
+			f.Imports = append(f.Imports, path)
+		}
+	}
+
+	var buffer bytes.Buffer
+	if err := facadeTemplate.Execute(&buffer, f); err != nil {
+		log.Fatalf("cannot generate the façade: %v", err)
+	}
+
+	source, err := format.Source(buffer.Bytes())
+	if err != nil {
+		log.Fatalf("cannot format the generated source: %v\n%s", err, buffer.String())
+	}
+
+	if output == "" {
+		os.Stdout.Write(source)
+		return
+	}
+	if err := os.WriteFile(output, source, 0o644); err != nil {
+		log.Fatalf("cannot write %s: %v", output, err)
+	}
+}
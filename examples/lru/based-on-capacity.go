@@ -0,0 +1,47 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"cachito/lru"
+	"log"
+)
+
+func main() {
+	/*
+		##################################################################
+		Example 3:
+		LRU with built-in eviction based on capacity
+		##################################################################
+	*/
+
+	// 1. Create your LRU with the maximum amount of entries. No metadata is needed
+	cache := lru.NewWithCapacity[string, int](3, struct{}{})
+
+	// 2. Handlers are optional, but still available
+	cache.OnDelete(func(metadata *struct{}, entry lru.Entry[string, int], reason lru.RemovalReason) error {
+		log.Printf("Entry removed: %v (%v)", entry.Key, reason)
+		return nil
+	})
+
+	// 3. Use your LRU
+	cache.CreateElement("a", 1)
+	cache.CreateElement("b", 2)
+	cache.CreateElement("c", 3)
+	cache.CreateElement("d", 4) // Eviction will happen here
+	cache.CreateElement("e", 5) // Eviction will happen here
+}
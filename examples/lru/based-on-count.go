@@ -40,20 +40,20 @@ func main() {
 		MaxItems:     3,
 		CurrentCount: 0,
 	}
-	cache := lru.New(customCacheMetadata)
+	cache := lru.New[string, int](customCacheMetadata)
 
 	// 2. Define what to do with that information to perform evictions when needed
-	cache.ShouldEvict(func(metadata *Example01__CacheMetadataT, entry lru.Entry) bool {
+	cache.ShouldEvict(func(metadata *Example01__CacheMetadataT, entry lru.Entry[string, int], candidate lru.Entry[string, int]) bool {
 		log.Printf("Items count currently stored: %v", metadata.CurrentCount)
 		return metadata.CurrentCount > metadata.MaxItems
 	})
 
-	cache.OnInsert(func(metadata *Example01__CacheMetadataT, entry lru.Entry) error {
+	cache.OnInsert(func(metadata *Example01__CacheMetadataT, entry lru.Entry[string, int]) error {
 		metadata.CurrentCount++
 		return nil
 	})
 
-	cache.OnDelete(func(metadata *Example01__CacheMetadataT, entry lru.Entry) error {
+	cache.OnDelete(func(metadata *Example01__CacheMetadataT, entry lru.Entry[string, int], reason lru.RemovalReason) error {
 		metadata.CurrentCount--
 		return nil
 	})
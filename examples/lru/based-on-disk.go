@@ -47,28 +47,26 @@ func main() {
 		MaxDiskUtilizationBytes:     30000,
 		CurrentDiskUtilizationBytes: 0,
 	}
-	cache := lru.New(customCacheMetadata)
+	cache := lru.New[string, Example02__CustomValueRepresentation](customCacheMetadata)
 
 	// 2. Define what to do with that information to perform evictions when needed
-	cache.ShouldEvict(func(metadata *Example02__CacheMetadataT, entry lru.Entry) bool {
+	cache.ShouldEvict(func(metadata *Example02__CacheMetadataT, entry lru.Entry[string, Example02__CustomValueRepresentation],
+		candidate lru.Entry[string, Example02__CustomValueRepresentation]) bool {
 
-		entryValue := entry.Value.(Example02__CustomValueRepresentation)
-		futureDiskUtilizationBytes := metadata.CurrentDiskUtilizationBytes + entryValue.FileSizeBytes
+		futureDiskUtilizationBytes := metadata.CurrentDiskUtilizationBytes + entry.Value.FileSizeBytes
 
-		log.Printf("Current total size: %v", metadata.CurrentDiskUtilizationBytes)
+		log.Printf("Current total size: %v, next candidate: %v", metadata.CurrentDiskUtilizationBytes, candidate.Value.FilePath)
 
 		return futureDiskUtilizationBytes > metadata.MaxDiskUtilizationBytes
 	})
 
-	cache.OnInsert(func(metadata *Example02__CacheMetadataT, entry lru.Entry) error {
-		entryValue := entry.Value.(Example02__CustomValueRepresentation)
-		metadata.CurrentDiskUtilizationBytes += entryValue.FileSizeBytes
+	cache.OnInsert(func(metadata *Example02__CacheMetadataT, entry lru.Entry[string, Example02__CustomValueRepresentation]) error {
+		metadata.CurrentDiskUtilizationBytes += entry.Value.FileSizeBytes
 		return nil
 	})
 
-	cache.OnDelete(func(metadata *Example02__CacheMetadataT, entry lru.Entry) error {
-		entryValue := entry.Value.(Example02__CustomValueRepresentation)
-		metadata.CurrentDiskUtilizationBytes -= entryValue.FileSizeBytes
+	cache.OnDelete(func(metadata *Example02__CacheMetadataT, entry lru.Entry[string, Example02__CustomValueRepresentation], reason lru.RemovalReason) error {
+		metadata.CurrentDiskUtilizationBytes -= entry.Value.FileSizeBytes
 		return nil
 	})
 
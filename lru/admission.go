@@ -0,0 +1,118 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lru
+
+import (
+	"slices"
+	"time"
+)
+
+const (
+	// defaultLoadTimeSamples is the amount of load times kept when none is configured
+	defaultLoadTimeSamples = 1024
+
+	// minLoadTimeSamples is the amount of load times needed before auto-tuning the threshold
+	minLoadTimeSamples = 32
+
+	// loadTimeTuneInterval is the amount of loads between two threshold recalculations
+	loadTimeTuneInterval = 64
+)
+
+// LoadTimeAdmission configures which values loaded by GetOrCreate are worth caching,
+// based on how long the loader took. Values that are fast to fetch again are returned
+// to the caller but not inserted, leaving the space to the expensive ones.
+type LoadTimeAdmission struct {
+	// MinLoadTime is the threshold below which loaded values are not cached
+	MinLoadTime time.Duration
+
+	// Percentile, between 0 and 1, auto-tunes the threshold to that percentile
+	// of the recent load times, replacing MinLoadTime once enough loads were observed.
+	// Zero disables auto-tuning.
+	Percentile float64
+
+	// Samples is the amount of recent load times considered for auto-tuning
+	Samples int
+}
+
+// loadTimes keeps the state of the load time admission
+type loadTimes struct {
+	config    LoadTimeAdmission
+	samples   []time.Duration // Ring buffer of the recent load times
+	next      int
+	loads     int
+	threshold time.Duration
+}
+
+// SetLoadTimeAdmission enables the admission of loaded values based on their load time.
+// A nil configuration disables it, so every loaded value is cached.
+func (c *LRU[K, V, MetaT]) SetLoadTimeAdmission(admission *LoadTimeAdmission) {
+	c.lock()
+	defer c.mu.Unlock()
+
+	if admission == nil {
+		c.loadTimes = nil
+		return
+	}
+
+	config := *admission
+	if config.Samples <= 0 {
+		config.Samples = defaultLoadTimeSamples
+	}
+	c.loadTimes = &loadTimes{
+		config:    config,
+		samples:   make([]time.Duration, 0, config.Samples),
+		threshold: config.MinLoadTime,
+	}
+}
+
+// admitLoadUnsafe records a load time and decides whether its value must be cached,
+// without locking the LRU
+func (c *LRU[K, V, MetaT]) admitLoadUnsafe(elapsed time.Duration) bool {
+	if c.loadTimes == nil {
+		return true
+	}
+	lt := c.loadTimes
+
+	if len(lt.samples) < lt.config.Samples {
+		lt.samples = append(lt.samples, elapsed)
+	} else {
+		lt.samples[lt.next] = elapsed
+		lt.next = (lt.next + 1) % lt.config.Samples
+	}
+	lt.loads++
+
+	if lt.config.Percentile > 0 && len(lt.samples) >= minLoadTimeSamples && lt.loads%loadTimeTuneInterval == 0 {
+		sorted := slices.Clone(lt.samples)
+		slices.Sort(sorted)
+		index := int(lt.config.Percentile * float64(len(sorted)-1))
+		lt.threshold = sorted[min(max(index, 0), len(sorted)-1)]
+	}
+
+	return elapsed >= lt.threshold
+}
+
+// LoadTimeThreshold returns the current load time below which loaded values are not cached.
+// It is zero when the load time admission is disabled.
+func (c *LRU[K, V, MetaT]) LoadTimeThreshold() time.Duration {
+	c.rlock()
+	defer c.mu.RUnlock()
+
+	if c.loadTimes == nil {
+		return 0
+	}
+	return c.loadTimes.threshold
+}
@@ -0,0 +1,169 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lru
+
+// AsyncHandlers configures the asynchronous execution of the OnInsert, OnDelete
+// and OnAccess handlers, set with SetAsyncHandlers.
+type AsyncHandlers struct {
+	// Workers is the amount of goroutines running handlers. Defaults to one
+	Workers int
+
+	// QueueSize is the amount of handler calls that can wait for a worker
+	QueueSize int
+
+	// DropWhenFull discards the handler calls that do not fit in the queue,
+	// counting them in Stats. Otherwise, the cache operation producing them
+	// blocks until there is room in the queue, holding the cache lock. Blocking handlers
+	// must not call cache methods then, or they could deadlock with a full queue
+	DropWhenFull bool
+
+	// OnError receives the errors returned by the handlers, which can no longer
+	// make the operations that produced them fail
+	OnError func(err error)
+}
+
+// handlerPool runs handler calls outside the cache lock
+type handlerPool struct {
+	queue        chan func() error
+	dropWhenFull bool
+	done         chan struct{}
+}
+
+// SetAsyncHandlers makes the OnInsert, OnDelete and OnAccess handlers run in a pool
+// of workers, outside the cache lock, so a slow handler (file removal, network call)
+// does not stall every other operation. Handlers then receive a copy of the entry
+// and the pointer to the metadata, so any access to the metadata must be synchronized,
+// and calls for the same key may run in any order when there are several workers.
+// Their errors are reported to OnError instead of failing the operations, e.g. a failing
+// OnDelete no longer keeps the entry. Other handlers keep running synchronously.
+// Any previous pool is stopped first, waiting for its pending calls.
+func (c *LRU[K, V, MetaT]) SetAsyncHandlers(config AsyncHandlers) {
+	c.StopAsyncHandlers()
+
+	pool := &handlerPool{
+		queue:        make(chan func() error, max(config.QueueSize, 0)),
+		dropWhenFull: config.DropWhenFull,
+		done:         make(chan struct{}),
+	}
+
+	workers := max(config.Workers, 1)
+	finished := make(chan struct{}, workers)
+	for range workers {
+		go func() {
+			defer func() { finished <- struct{}{} }()
+			for run := range pool.queue {
+				if err := run(); err != nil && config.OnError != nil {
+					config.OnError(err)
+				}
+			}
+		}()
+	}
+	go func() {
+		for range workers {
+			<-finished
+		}
+		close(pool.done)
+	}()
+
+	c.lock()
+	c.handlerPool = pool
+	c.mu.Unlock()
+}
+
+// StopAsyncHandlers makes the handlers run synchronously again,
+// waiting for the pending calls to finish.
+func (c *LRU[K, V, MetaT]) StopAsyncHandlers() {
+	c.lock()
+	pool := c.handlerPool
+	c.handlerPool = nil
+	c.mu.Unlock()
+
+	if pool == nil {
+		return
+	}
+	close(pool.queue)
+	<-pool.done
+}
+
+// dispatchUnsafe queues a handler call to the pool without locking the LRU
+func (c *LRU[K, V, MetaT]) dispatchUnsafe(run func() error) {
+	if faults := c.faults.Load(); faults != nil && faults.hit(faults.AsyncDropRate) {
+		c.counters.droppedHandlerCalls.Add(1)
+		return
+	}
+	if !c.handlerPool.dropWhenFull {
+		c.handlerPool.queue <- run
+		return
+	}
+
+	select {
+	case c.handlerPool.queue <- run:
+	default:
+		c.counters.droppedHandlerCalls.Add(1)
+	}
+}
+
+// runOnInsertUnsafe runs the OnInsert handler, if any, without locking the LRU
+func (c *LRU[K, V, MetaT]) runOnInsertUnsafe(entry Entry[K, V]) error {
+	entry = c.handlerEntryUnsafe(entry)
+	handler := c.onInsertHandler
+	guard := c.handlerGuardUnsafe()
+	switch {
+	case handler == nil:
+		return nil
+	case c.handlerPool == nil:
+		return runHandler("OnInsert", entry.Key, guard, func() error { return handler(&c.Metadata, entry) })
+	}
+	c.dispatchUnsafe(func() error {
+		return runHandler("OnInsert", entry.Key, guard, func() error { return handler(&c.Metadata, entry) })
+	})
+	return nil
+}
+
+// runOnDeleteUnsafe runs the OnDelete handler, if any, without locking the LRU
+func (c *LRU[K, V, MetaT]) runOnDeleteUnsafe(entry Entry[K, V], reason RemovalReason) error {
+	entry = c.handlerEntryUnsafe(entry)
+	handler := c.onDeleteHandler
+	guard := c.handlerGuardUnsafe()
+	switch {
+	case handler == nil:
+		return nil
+	case c.handlerPool == nil:
+		return runHandler("OnDelete", entry.Key, guard, func() error { return handler(&c.Metadata, entry, reason) })
+	}
+	c.dispatchUnsafe(func() error {
+		return runHandler("OnDelete", entry.Key, guard, func() error { return handler(&c.Metadata, entry, reason) })
+	})
+	return nil
+}
+
+// runOnAccessUnsafe runs the OnAccess handler, if any, without locking the LRU
+func (c *LRU[K, V, MetaT]) runOnAccessUnsafe(entry Entry[K, V]) error {
+	entry = c.handlerEntryUnsafe(entry)
+	handler := c.onAccessHandler
+	guard := c.handlerGuardUnsafe()
+	switch {
+	case handler == nil:
+		return nil
+	case c.handlerPool == nil:
+		return runHandler("OnAccess", entry.Key, guard, func() error { return handler(&c.Metadata, entry) })
+	}
+	c.dispatchUnsafe(func() error {
+		return runHandler("OnAccess", entry.Key, guard, func() error { return handler(&c.Metadata, entry) })
+	})
+	return nil
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lru
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestAsyncHandlersDropWhenFull(t *testing.T) {
+	c := New[string, int](struct{}{})
+	started, release := make(chan struct{}), make(chan struct{})
+	var mu sync.Mutex
+	var handled []string
+	c.OnInsert(func(_ *struct{}, entry Entry[string, int]) error {
+		if entry.Key == "a" {
+			close(started)
+			<-release
+		}
+		mu.Lock()
+		handled = append(handled, entry.Key)
+		mu.Unlock()
+		if entry.Key == "b" {
+			return errors.New("handler failed")
+		}
+		return nil
+	})
+
+	var reported []error
+	c.SetAsyncHandlers(AsyncHandlers{
+		Workers:      1,
+		QueueSize:    1,
+		DropWhenFull: true,
+		OnError:      func(err error) { reported = append(reported, err) },
+	})
+
+	// The worker is busy with a, so b waits in the queue and c does not fit
+	if err := c.CreateElement("a", 1); err != nil {
+		t.Fatal(err)
+	}
+	<-started
+	for _, key := range []string{"b", "c"} {
+		if err := c.CreateElement(key, 1); err != nil {
+			t.Fatalf("got error %v inserting %s, want it reported to OnError", err, key)
+		}
+	}
+	close(release)
+	c.StopAsyncHandlers()
+
+	if !slices.Equal(handled, []string{"a", "b"}) {
+		t.Fatalf("got handled keys %v, want [a b]", handled)
+	}
+	if got := c.Stats().DroppedHandlerCalls; got != 1 {
+		t.Fatalf("got %d dropped calls, want 1", got)
+	}
+	if len(reported) != 1 {
+		t.Fatalf("got reported errors %v, want the one of b", reported)
+	}
+	if c.Len() != 3 {
+		t.Fatalf("got %d entries, want 3", c.Len())
+	}
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lru
+
+import (
+	"errors"
+	"time"
+)
+
+// InvalidateResult is the outcome of the invalidation of a single key.
+type InvalidateResult[K comparable] struct {
+	Key   K
+	Found bool  // Whether the key was present in the cache
+	Err   error // Error returned by the OnDelete handler, if any
+}
+
+// InvalidateBatch removes all the given keys acquiring the cache lock only once,
+// running the OnDelete handler for each of them. Duplicated keys are removed once.
+// The results are returned in the order each key first appeared.
+func (c *LRU[K, V, MetaT]) InvalidateBatch(keys []K) []InvalidateResult[K] {
+	lockErr := c.lockChecked()
+	if lockErr == nil {
+		defer c.mu.Unlock()
+	}
+
+	seen := make(map[K]struct{}, len(keys))
+	results := make([]InvalidateResult[K], 0, len(keys))
+
+	for _, key := range keys {
+		if _, duplicated := seen[key]; duplicated {
+			continue
+		}
+		seen[key] = struct{}{}
+
+		result := InvalidateResult[K]{Key: key}
+		if lockErr != nil {
+			result.Err = lockErr
+			results = append(results, result)
+			continue
+		}
+		if err := c.writableUnsafe(); err != nil {
+			result.Err = err
+			results = append(results, result)
+			continue
+		}
+
+		_, result.Found = c.index[key]
+		result.Err = c.deleteElementUnsafe(key)
+		results = append(results, result)
+	}
+	return results
+}
+
+// MGet returns the values associated with the given keys acquiring the cache lock only once.
+// Missing keys are not present in the result. Every found entry is moved to the front
+// and runs the OnAccess handler. Handler errors do not stop the batch, they are returned joined.
+func (c *LRU[K, V, MetaT]) MGet(keys []K) (map[K]V, error) {
+	if err := c.lockChecked(); err != nil {
+		return nil, err
+	}
+	defer c.mu.Unlock()
+
+	var errs []error
+	values := make(map[K]V, len(keys))
+
+	for _, key := range keys {
+		value, found, err := c.getElementUnsafe(key)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if found {
+			values[key] = value
+		}
+	}
+	return values, errors.Join(errs...)
+}
+
+// MSet inserts or updates all the given entries acquiring the cache lock only once,
+// running the eviction loop and the OnInsert handler for each of them.
+// The insertion order is unspecified. Errors do not stop the batch, they are returned joined.
+func (c *LRU[K, V, MetaT]) MSet(entries map[K]V) error {
+	if err := c.lockChecked(); err != nil {
+		return err
+	}
+	defer c.mu.Unlock()
+
+	var errs []error
+	for key, value := range entries {
+		if err := c.createElementUnsafe(key, value, nil); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// MDelete removes all the given keys acquiring the cache lock only once,
+// running the OnDelete handler for each of them. Errors do not stop the batch,
+// they are returned joined. Use InvalidateBatch to get the result of each key.
+func (c *LRU[K, V, MetaT]) MDelete(keys []K) error {
+	var errs []error
+	for _, result := range c.InvalidateBatch(keys) {
+		if result.Err != nil {
+			errs = append(errs, result.Err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// DeleteIf removes every entry matching fn under a single lock acquisition,
+// so entries inserted concurrently cannot slip between the scan and the removal.
+// It returns how many entries were removed. The OnDelete handler runs for each of them,
+// and its errors do not stop the scan: they are returned joined, and the failing entries are kept.
+// Expired entries are not considered. fn must not call any other cache method:
+// the ones modifying the cache fail with ErrReentrantMutation.
+func (c *LRU[K, V, MetaT]) DeleteIf(fn func(entry Entry[K, V]) bool) (int, error) {
+	if err := c.lockChecked(); err != nil {
+		return 0, err
+	}
+	defer c.mu.Unlock()
+
+	if err := c.writableUnsafe(); err != nil {
+		return 0, err
+	}
+
+	var matched []K
+	now := time.Now()
+	for element := c.list.Front(); element != nil; element = element.Next() {
+		it := element.Value.(*item[K, V])
+		if !it.expired(now) && c.visitUnsafe(fn, it.entry) {
+			matched = append(matched, it.entry.Key)
+		}
+	}
+
+	deleted := 0
+	var errs []error
+	for _, key := range matched {
+		if err := c.deleteElementUnsafe(key); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		deleted++
+	}
+	return deleted, errors.Join(errs...)
+}
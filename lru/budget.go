@@ -0,0 +1,42 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lru
+
+// SetEvictionBudget caps how many entries a single insertion may evict, so inserting one
+// enormous value cannot wipe the whole cache while blocking every other operation.
+// The insertion that needs more room fails with ErrEvictionBudgetExceeded, keeping the
+// entries it already evicted out. onExceeded, when not nil, is called with the rejected entry
+// and the amount of entries evicted for it. It runs under the cache lock, so it must not
+// call cache methods. Zero disables the budget.
+func (c *LRU[K, V, MetaT]) SetEvictionBudget(budget int, onExceeded func(entry Entry[K, V], evicted int)) {
+	c.lock()
+	defer c.mu.Unlock()
+	c.evictionBudget = max(budget, 0)
+	c.onBudgetExceeded = onExceeded
+}
+
+// checkEvictionBudgetUnsafe returns ErrEvictionBudgetExceeded when an insertion that already
+// evicted the given amount of entries needs to evict another one, without locking the LRU
+func (c *LRU[K, V, MetaT]) checkEvictionBudgetUnsafe(entry Entry[K, V], evicted int) error {
+	if c.evictionBudget == 0 || evicted < c.evictionBudget {
+		return nil
+	}
+	if c.onBudgetExceeded != nil {
+		c.onBudgetExceeded(entry, evicted)
+	}
+	return ErrEvictionBudgetExceeded
+}
@@ -0,0 +1,38 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lru
+
+import "time"
+
+// SetBypass enables or disables the bypass mode, where GetOrCreate and GetOrCreateCtx skip
+// the cache and return what the loader produces, without storing it nor coalescing the calls.
+// Lookups are still counted as hits or misses, as if the cache was used, so operators can
+// measure its real benefit, or tell staleness issues apart from loader ones.
+// The loads are counted in Stats.Bypassed. The other operations are not affected.
+func (c *LRU[K, V, MetaT]) SetBypass(enabled bool) {
+	c.lock()
+	defer c.mu.Unlock()
+	c.bypass = enabled
+}
+
+// bypassUnsafe counts a lookup skipped by the bypass mode, without touching
+// the entry nor locking the LRU
+func (c *LRU[K, V, MetaT]) bypassUnsafe(key K) {
+	element, found := c.index[key]
+	c.recordLookupUnsafe(found && !element.Value.(*item[K, V]).expired(time.Now()))
+	c.counters.bypassed.Add(1)
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lru
+
+import "errors"
+
+// The AddOn* methods register a handler after the ones already set, instead of
+// replacing them like their On* counterparts, so independent layers (metrics,
+// persistence...) can be composed. Chained handlers always run in the order they
+// were added, all of them, even when some fail: their errors are joined and returned
+// as the error of the handler, with the same consequences as a single failing one.
+
+// AddOnInsert chains a handler to be called when a new entry is created.
+func (c *LRU[K, V, MetaT]) AddOnInsert(handler func(metadata *MetaT, entry Entry[K, V]) error) {
+	c.onInsertHandler = chainHandlers(c.onInsertHandler, handler)
+}
+
+// AddOnDelete chains a handler to be called when an entry is removed or replaced.
+func (c *LRU[K, V, MetaT]) AddOnDelete(handler func(metadata *MetaT, entry Entry[K, V], reason RemovalReason) error) {
+	previous := c.onDeleteHandler
+	if previous == nil {
+		c.onDeleteHandler = handler
+		return
+	}
+	c.onDeleteHandler = func(metadata *MetaT, entry Entry[K, V], reason RemovalReason) error {
+		return errors.Join(previous(metadata, entry, reason), handler(metadata, entry, reason))
+	}
+}
+
+// AddOnAccess chains a handler to be called when an entry is accessed.
+func (c *LRU[K, V, MetaT]) AddOnAccess(handler func(metadata *MetaT, entry Entry[K, V]) error) {
+	c.onAccessHandler = chainHandlers(c.onAccessHandler, handler)
+}
+
+// AddOnExpire chains a handler to be called when an expired entry is removed.
+func (c *LRU[K, V, MetaT]) AddOnExpire(handler func(metadata *MetaT, entry Entry[K, V]) error) {
+	c.onExpireHandler = chainHandlers(c.onExpireHandler, handler)
+}
+
+// chainHandlers returns a handler running previous, if any, and then next, joining their errors
+func chainHandlers[K comparable, V any, MetaT any](previous, next func(metadata *MetaT, entry Entry[K, V]) error) func(metadata *MetaT, entry Entry[K, V]) error {
+	if previous == nil {
+		return next
+	}
+	return func(metadata *MetaT, entry Entry[K, V]) error {
+		return errors.Join(previous(metadata, entry), next(metadata, entry))
+	}
+}
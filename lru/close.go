@@ -0,0 +1,41 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lru
+
+// Close stops the background work of the cache, the janitor and the asynchronous
+// handlers, waiting for it to finish. Afterwards, the operations that would modify
+// the cache are rejected with ErrCacheClosed, while reads keep working.
+// Closing a cache more than once is harmless.
+func (c *LRU[K, V, MetaT]) Close() {
+	c.StopJanitor()
+	c.StopAsyncHandlers()
+
+	c.lock()
+	defer c.mu.Unlock()
+	c.closed = true
+}
+
+// writableUnsafe returns the error rejecting modifications of the cache, if any, without locking it
+func (c *LRU[K, V, MetaT]) writableUnsafe() error {
+	switch {
+	case c.closed:
+		return ErrCacheClosed
+	case c.frozen:
+		return ErrFrozen
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lru
+
+import "context"
+
+// Context returns the context of the operation that triggered the handler receiving the entry,
+// so handlers can honour its deadline or propagate its tracing information.
+// context.Background is returned for the operations not started by a context-aware variant.
+func (e Entry[K, V]) Context() context.Context {
+	if e.ctx == nil {
+		return context.Background()
+	}
+	return e.ctx
+}
+
+// GetElementCtx behaves like GetElement, passing ctx to the handlers it runs.
+// The context error is returned, without accessing the cache, when ctx is already done.
+func (c *LRU[K, V, MetaT]) GetElementCtx(ctx context.Context, key K) (V, error) {
+	if err := ctx.Err(); err != nil {
+		var zero V
+		return zero, err
+	}
+
+	if err := c.lockChecked(); err != nil {
+		var zero V
+		return zero, err
+	}
+	defer c.mu.Unlock()
+	defer c.bindContextUnsafe(ctx)()
+
+	value, found, err := c.getElementUnsafe(key)
+	return value, notFound(found, err)
+}
+
+// CreateElementCtx behaves like CreateElement, passing ctx to the handlers it runs.
+// The context error is returned, without modifying the cache, when ctx is already done.
+func (c *LRU[K, V, MetaT]) CreateElementCtx(ctx context.Context, key K, value V) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if err := c.lockChecked(); err != nil {
+		return err
+	}
+	defer c.mu.Unlock()
+	defer c.bindContextUnsafe(ctx)()
+	return c.createElementUnsafe(key, value, nil)
+}
+
+// DeleteElementCtx behaves like DeleteElement, passing ctx to the handlers it runs.
+// The context error is returned, without modifying the cache, when ctx is already done.
+func (c *LRU[K, V, MetaT]) DeleteElementCtx(ctx context.Context, key K) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if err := c.lockChecked(); err != nil {
+		return err
+	}
+	defer c.mu.Unlock()
+	defer c.bindContextUnsafe(ctx)()
+
+	if err := c.writableUnsafe(); err != nil {
+		return err
+	}
+	return c.deleteElementUnsafe(key)
+}
+
+// GetOrCreateCtx behaves like GetOrCreate, passing ctx to the loader and to the handlers it runs.
+// Callers waiting for a loader started by another one stop waiting when their context is done.
+// The loader receives the context of the caller that started it, so its cancellation
+// fails every caller coalesced on that key.
+func (c *LRU[K, V, MetaT]) GetOrCreateCtx(ctx context.Context, key K, loader func(ctx context.Context) (V, error)) (V, error) {
+	if err := ctx.Err(); err != nil {
+		var zero V
+		return zero, err
+	}
+	return c.getOrCreate(ctx, key, loader)
+}
+
+// bindContextUnsafe sets the context of the operation holding the lock,
+// and returns the function restoring the previous one
+func (c *LRU[K, V, MetaT]) bindContextUnsafe(ctx context.Context) func() {
+	previous := c.opCtx
+	c.opCtx = ctx
+	return func() { c.opCtx = previous }
+}
+
+// contextUnsafe returns the context of the operation holding the lock, without locking the LRU
+func (c *LRU[K, V, MetaT]) contextUnsafe() context.Context {
+	if c.opCtx == nil {
+		return context.Background()
+	}
+	return c.opCtx
+}
+
+// handlerEntryUnsafe returns a copy of the entry carrying the context of the operation
+// holding the lock, to be passed to a handler, without locking the LRU
+func (c *LRU[K, V, MetaT]) handlerEntryUnsafe(entry Entry[K, V]) Entry[K, V] {
+	entry.ctx = c.opCtx
+	return entry
+}
@@ -0,0 +1,48 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lru
+
+// Integer is the constraint satisfied by the values that can be used as counters.
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// Increment atomically adds delta to the value associated with the given key,
+// and returns the result. Missing and expired entries count as zero, so they are created.
+// Existing entries keep their deadline, so a counter with a ttl works as a fixed-window rate counter.
+// It is a function rather than a method because only caches with integer values support it,
+// e.g. lru.Increment(cache, "requests", 1)
+func Increment[K comparable, V Integer, MetaT any](c *LRU[K, V, MetaT], key K, delta V) (V, error) {
+	if err := c.lockChecked(); err != nil {
+		return 0, err
+	}
+	defer c.mu.Unlock()
+
+	value, _ := c.peekUnsafe(key)
+	value += delta
+	if err := c.createElementUnsafe(key, value, c.updateOptionsUnsafe(key)); err != nil {
+		return 0, err
+	}
+	return value, nil
+}
+
+// Decrement atomically subtracts delta from the value associated with the given key,
+// and returns the result. It behaves like Increment otherwise.
+func Decrement[K comparable, V Integer, MetaT any](c *LRU[K, V, MetaT], key K, delta V) (V, error) {
+	return Increment(c, key, -delta)
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lru
+
+import (
+	"context"
+	"time"
+)
+
+// SetKeyDecorator sets a function deriving the effective cache key from the request
+// metadata carried by a context (tenant, locale, experiment flags...) and the key
+// given by the caller. It is applied by every operation of the views returned by
+// WithContext, so gets, inserts and invalidations always agree on the effective key.
+// A nil decorator leaves keys untouched.
+func (c *LRU[K, V, MetaT]) SetKeyDecorator(decorator func(ctx context.Context, key K) K) {
+	c.lock()
+	defer c.mu.Unlock()
+	c.keyDecorator = decorator
+}
+
+// Decorated is a view of an LRU bound to a context, whose operations
+// decorate the given keys with the function set by SetKeyDecorator.
+// It is cheap to create, so a new one can be used for each request.
+type Decorated[K comparable, V any, MetaT any] struct {
+	parent    *LRU[K, V, MetaT]
+	ctx       context.Context
+	decorator func(ctx context.Context, key K) K
+}
+
+// WithContext returns a view of the cache whose operations decorate keys
+// with the request metadata carried by ctx.
+func (c *LRU[K, V, MetaT]) WithContext(ctx context.Context) *Decorated[K, V, MetaT] {
+	c.rlock()
+	defer c.mu.RUnlock()
+
+	return &Decorated[K, V, MetaT]{
+		parent:    c,
+		ctx:       ctx,
+		decorator: c.keyDecorator,
+	}
+}
+
+// Key returns the effective cache key for the given key.
+func (d *Decorated[K, V, MetaT]) Key(key K) K {
+	if d.decorator == nil {
+		return key
+	}
+	return d.decorator(d.ctx, key)
+}
+
+// GetElement returns the value associated with the given key, like LRU.GetElementCtx.
+func (d *Decorated[K, V, MetaT]) GetElement(key K) (V, error) {
+	return d.parent.GetElementCtx(d.ctx, d.Key(key))
+}
+
+// Peek returns the value associated with the given key without updating its recency, like LRU.Peek.
+func (d *Decorated[K, V, MetaT]) Peek(key K) (V, bool) {
+	return d.parent.Peek(d.Key(key))
+}
+
+// Contains reports whether the given key is present, like LRU.Contains.
+func (d *Decorated[K, V, MetaT]) Contains(key K) bool {
+	return d.parent.Contains(d.Key(key))
+}
+
+// CreateElement inserts or updates an entry, like LRU.CreateElementCtx.
+func (d *Decorated[K, V, MetaT]) CreateElement(key K, value V) error {
+	return d.parent.CreateElementCtx(d.ctx, d.Key(key), value)
+}
+
+// CreateElementWithTTL inserts or updates an entry that expires after ttl, like LRU.CreateElementWithTTL.
+func (d *Decorated[K, V, MetaT]) CreateElementWithTTL(key K, value V, ttl time.Duration) error {
+	return d.parent.CreateElementWithTTL(d.Key(key), value, ttl)
+}
+
+// DeleteElement removes the entry associated with the given key, like LRU.DeleteElementCtx.
+func (d *Decorated[K, V, MetaT]) DeleteElement(key K) error {
+	return d.parent.DeleteElementCtx(d.ctx, d.Key(key))
+}
+
+// GetOrCreate returns the value associated with the given key, calling loader
+// to create it when missing, like LRU.GetOrCreateCtx.
+func (d *Decorated[K, V, MetaT]) GetOrCreate(key K, loader func(ctx context.Context) (V, error)) (V, error) {
+	return d.parent.GetOrCreateCtx(d.ctx, d.Key(key), loader)
+}
+
+// Pop removes the entry associated with the given key and returns its value, like LRU.Pop.
+func (d *Decorated[K, V, MetaT]) Pop(key K) (V, bool, error) {
+	return d.parent.Pop(d.Key(key))
+}
+
+// Touch sets a new ttl for the entry associated with the given key, like LRU.Touch.
+func (d *Decorated[K, V, MetaT]) Touch(key K, ttl time.Duration, promote bool) (bool, error) {
+	return d.parent.Touch(d.Key(key), ttl, promote)
+}
+
+// InvalidateBatch removes all the given keys, like LRU.InvalidateBatch.
+// The results carry the keys given by the caller, not the effective ones,
+// and keys decorated into the same effective key are removed once.
+func (d *Decorated[K, V, MetaT]) InvalidateBatch(keys []K) []InvalidateResult[K] {
+	effective := make([]K, len(keys))
+	given := make(map[K]K, len(keys))
+	for i, key := range keys {
+		effective[i] = d.Key(key)
+		if _, found := given[effective[i]]; !found {
+			given[effective[i]] = key
+		}
+	}
+
+	results := d.parent.InvalidateBatch(effective)
+	for i := range results {
+		results[i].Key = given[results[i].Key]
+	}
+	return results
+}
+
+// MDelete removes all the given keys, like LRU.MDelete.
+func (d *Decorated[K, V, MetaT]) MDelete(keys []K) error {
+	effective := make([]K, len(keys))
+	for i, key := range keys {
+		effective[i] = d.Key(key)
+	}
+	return d.parent.MDelete(effective)
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lru
+
+import (
+	"context"
+	"testing"
+)
+
+type tenantKey struct{}
+
+// tenantCache returns a cache decorating keys with the tenant carried by the context
+func tenantCache() *LRU[string, int, struct{}] {
+	c := New[string, int](struct{}{})
+	c.SetKeyDecorator(func(ctx context.Context, key string) string {
+		tenant, _ := ctx.Value(tenantKey{}).(string)
+		return tenant + "/" + key
+	})
+	return c
+}
+
+func TestDecoratedGetOrCreate(t *testing.T) {
+	c := tenantCache()
+	view := c.WithContext(context.WithValue(context.Background(), tenantKey{}, "acme"))
+
+	value, err := view.GetOrCreate("a", func(context.Context) (int, error) { return 1, nil })
+	if err != nil || value != 1 {
+		t.Fatalf("got (%d, %v), want (1, nil)", value, err)
+	}
+	if !c.Contains("acme/a") || c.Contains("a") {
+		t.Fatalf("got keys %v, want [acme/a]", c.Keys())
+	}
+
+	value, found, err := view.Pop("a")
+	if err != nil || !found || value != 1 {
+		t.Fatalf("got (%d, %t, %v), want (1, true, nil)", value, found, err)
+	}
+}
+
+func TestDecoratedInvalidateBatch(t *testing.T) {
+	c := tenantCache()
+	view := c.WithContext(context.WithValue(context.Background(), tenantKey{}, "acme"))
+	for _, key := range []string{"a", "acme/a", "acme/b"} {
+		if err := c.CreateElement(key, 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	results := view.InvalidateBatch([]string{"a", "c", "a"})
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want 2", len(results))
+	}
+	if results[0].Key != "a" || !results[0].Found || results[1].Key != "c" || results[1].Found {
+		t.Fatalf("got results %+v, want a found and c missing", results)
+	}
+	if err := view.MDelete([]string{"b"}); err != nil {
+		t.Fatal(err)
+	}
+	if keys := c.Keys(); len(keys) != 1 || keys[0] != "a" {
+		t.Fatalf("got keys %v, want [a]", keys)
+	}
+}
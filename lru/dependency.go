@@ -0,0 +1,140 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lru
+
+import (
+	"errors"
+	"fmt"
+)
+
+// AddDependency declares that the entry of the given key depends on the entry of parent,
+// so removing the parent, for any reason, also deletes the dependent entry.
+// Cascades are transitive, e.g. invalidating "article:7" can drop "article:7:rendered-html",
+// which in turn drops the entries depending on it. Dependencies are forgotten when
+// either entry is removed, so they must be declared again if the entry is inserted again.
+// It reports false when any of the entries is missing, and returns ErrDependencyCycle
+// when parent already depends, directly or not, on the given key.
+func (c *LRU[K, V, MetaT]) AddDependency(key, parent K) (bool, error) {
+	if err := c.lockChecked(); err != nil {
+		return false, err
+	}
+	defer c.mu.Unlock()
+
+	_, keyFound := c.index[key]
+	_, parentFound := c.index[parent]
+	if !keyFound || !parentFound {
+		return false, nil
+	}
+	if c.dependsOnUnsafe(parent, key) {
+		return false, ErrDependencyCycle
+	}
+
+	if c.dependents == nil {
+		c.dependents = make(map[K]map[K]struct{})
+		c.dependencies = make(map[K]map[K]struct{})
+	}
+	link(c.dependents, parent, key)
+	link(c.dependencies, key, parent)
+	return true, nil
+}
+
+// OnCascade sets a handler to be called when an entry is deleted because the parent
+// entry it depends on was removed. It runs before OnDelete. When it fails,
+// the dependent entry is kept, the cascade goes on with the other dependents, and
+// the removal of the parent returns the error wrapped in ErrCascadeFailed.
+func (c *LRU[K, V, MetaT]) OnCascade(handler func(metadata *MetaT, parent K, entry Entry[K, V]) error) {
+	c.onCascadeHandler = handler
+}
+
+// dependsOnUnsafe reports whether key depends, directly or transitively, on ancestor,
+// without locking the LRU. Keys depend on themselves.
+func (c *LRU[K, V, MetaT]) dependsOnUnsafe(key, ancestor K) bool {
+	if key == ancestor {
+		return true
+	}
+	for parent := range c.dependencies[key] {
+		if c.dependsOnUnsafe(parent, ancestor) {
+			return true
+		}
+	}
+	return false
+}
+
+// cascadeUnsafe forgets the dependencies of a removed entry and deletes the entries
+// depending on it, without locking the LRU. Every dependent is tried, and the errors
+// of the ones that could not be deleted are joined under ErrCascadeFailed.
+func (c *LRU[K, V, MetaT]) cascadeUnsafe(key K) error {
+	for parent := range c.dependencies[key] {
+		unlink(c.dependents, parent, key)
+	}
+	delete(c.dependencies, key)
+
+	dependents := c.dependents[key]
+	delete(c.dependents, key)
+
+	var failed, errs []error
+	for dependent := range dependents {
+		unlink(c.dependencies, dependent, key)
+
+		element, found := c.index[dependent]
+		if !found {
+			continue
+		}
+		if c.onCascadeHandler != nil {
+			entry := c.handlerEntryUnsafe(element.Value.(*item[K, V]).entry)
+			err := runHandler("OnCascade", dependent, c.handlerGuardUnsafe(), func() error {
+				return c.onCascadeHandler(&c.Metadata, key, entry)
+			})
+			if err != nil {
+				failed = append(failed, err)
+				continue
+			}
+		}
+
+		// Dependents removed despite an error, e.g. writing the journal, are not failures
+		removed, err := c.removeElementUnsafe(dependent, ReasonCascaded)
+		switch {
+		case err == nil:
+		case removed:
+			errs = append(errs, err)
+		default:
+			failed = append(failed, err)
+		}
+	}
+	if len(failed) > 0 {
+		errs = append(errs, fmt.Errorf("%w: %w", ErrCascadeFailed, errors.Join(failed...)))
+	}
+	return errors.Join(errs...)
+}
+
+// link adds b to the set of keys associated with a
+func link[K comparable](graph map[K]map[K]struct{}, a, b K) {
+	keys, found := graph[a]
+	if !found {
+		keys = make(map[K]struct{})
+		graph[a] = keys
+	}
+	keys[b] = struct{}{}
+}
+
+// unlink removes b from the set of keys associated with a, forgetting empty sets
+func unlink[K comparable](graph map[K]map[K]struct{}, a, b K) {
+	delete(graph[a], b)
+	if len(graph[a]) == 0 {
+		delete(graph, a)
+	}
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lru
+
+import (
+	"container/heap"
+	"time"
+)
+
+// expirations is a min-heap of the items that have a deadline, ordered by
+// the moment they can be removed, grace period included.
+// It allows finding the expired items without scanning the whole list.
+type expirations[K comparable, V any] []*item[K, V]
+
+func (e expirations[K, V]) Len() int { return len(e) }
+
+func (e expirations[K, V]) Less(i, j int) bool {
+	return e[i].removeAt().Before(e[j].removeAt())
+}
+
+func (e expirations[K, V]) Swap(i, j int) {
+	e[i], e[j] = e[j], e[i]
+	e[i].heapIndex = i
+	e[j].heapIndex = j
+}
+
+func (e *expirations[K, V]) Push(x any) {
+	it := x.(*item[K, V])
+	it.heapIndex = len(*e)
+	*e = append(*e, it)
+}
+
+func (e *expirations[K, V]) Pop() any {
+	old := *e
+	it := old[len(old)-1]
+	old[len(old)-1] = nil
+	it.heapIndex = -1
+	*e = old[:len(old)-1]
+	return it
+}
+
+// setDeadlineUnsafe changes the deadline of an item, keeping the expirations heap
+// up to date, without locking the LRU. A zero deadline means the item never expires.
+func (c *LRU[K, V, MetaT]) setDeadlineUnsafe(it *item[K, V], deadline time.Time) {
+	it.expiresAt = deadline
+
+	switch {
+	case deadline.IsZero() && it.heapIndex >= 0:
+		heap.Remove(&c.expirations, it.heapIndex)
+		c.cancelScheduleUnsafe(it.entry.Key)
+	case deadline.IsZero():
+		// Nothing to track
+	case it.heapIndex >= 0:
+		heap.Fix(&c.expirations, it.heapIndex)
+		c.scheduleUnsafe(it)
+	default:
+		heap.Push(&c.expirations, it)
+		c.scheduleUnsafe(it)
+	}
+}
+
+// forgetDeadlineUnsafe stops tracking the deadline of an item that is being removed
+// without locking the LRU
+func (c *LRU[K, V, MetaT]) forgetDeadlineUnsafe(it *item[K, V]) {
+	if it.heapIndex >= 0 {
+		heap.Remove(&c.expirations, it.heapIndex)
+		c.cancelScheduleUnsafe(it.entry.Key)
+	}
+}
+
+// Scheduler receives the deadlines of the entries, so huge sets of expirations can be
+// managed outside the process, e.g. by a delayed queue. When a deadline is reached,
+// the scheduler is expected to call Expire for the key.
+// Its methods are called while the cache is locked, so they must be fast
+// and must not call any cache method.
+type Scheduler[K comparable] interface {
+	// Schedule registers or moves the moment an entry expires, grace period included
+	Schedule(key K, deadline time.Time)
+
+	// Cancel deregisters an entry that no longer expires or was removed
+	Cancel(key K)
+}
+
+// SetScheduler sets an external scheduler notified every time an entry deadline
+// is set, changed or dropped. A nil scheduler disables the notifications.
+// Entries already stored with a deadline are scheduled right away.
+func (c *LRU[K, V, MetaT]) SetScheduler(scheduler Scheduler[K]) {
+	c.lock()
+	defer c.mu.Unlock()
+
+	c.scheduler = scheduler
+	for _, it := range c.expirations {
+		c.scheduleUnsafe(it)
+	}
+}
+
+// Expire removes the entry associated with the given key if it is expired and out
+// of its grace period, running the OnExpire handler, and reports whether it was removed.
+// It is meant to be called by external schedulers when a deadline is reached.
+func (c *LRU[K, V, MetaT]) Expire(key K) (bool, error) {
+	if err := c.lockChecked(); err != nil {
+		return false, err
+	}
+	defer c.mu.Unlock()
+
+	element, found := c.index[key]
+	if !found || !element.Value.(*item[K, V]).removable(time.Now()) {
+		return false, nil
+	}
+	return c.expireElementUnsafe(key)
+}
+
+// scheduleUnsafe notifies the external scheduler about the deadline of an item
+// without locking the LRU
+func (c *LRU[K, V, MetaT]) scheduleUnsafe(it *item[K, V]) {
+	if c.scheduler != nil {
+		c.scheduler.Schedule(it.entry.Key, it.removeAt())
+	}
+}
+
+// cancelScheduleUnsafe notifies the external scheduler that a key no longer expires
+// without locking the LRU
+func (c *LRU[K, V, MetaT]) cancelScheduleUnsafe(key K) {
+	if c.scheduler != nil {
+		c.scheduler.Cancel(key)
+	}
+}
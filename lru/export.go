@@ -0,0 +1,58 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lru
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Export writes the entries accepted by filter to w as JSONL, and returns how many were written.
+// A nil filter exports every entry, expired ones excepted. The output can be loaded into another cache
+// with Import using the ImportJSONL format. Entries are written from the least
+// to the most recently used, so importing them preserves the recency order.
+// The cache is read-locked during the whole export, and the operations modifying it
+// fail with ErrReentrantMutation when called from filter.
+func (c *LRU[K, V, MetaT]) Export(w io.Writer, filter func(entry Entry[K, V]) bool) (int, error) {
+	c.rlock()
+	defer c.mu.RUnlock()
+
+	encoder := json.NewEncoder(w)
+	exported := 0
+	now := time.Now()
+
+	for element := c.list.Back(); element != nil; element = element.Prev() {
+		it := element.Value.(*item[K, V])
+		entry := it.entry
+		if it.expired(now) || (filter != nil && !c.visitUnsafe(filter, entry)) {
+			continue
+		}
+
+		// Entries with a deadline are exported with their remaining lifetime
+		record := importRecord[K, V]{Key: entry.Key, Value: entry.Value}
+		if !it.expiresAt.IsZero() {
+			record.TTL = it.expiresAt.Sub(now).String()
+		}
+		if err := encoder.Encode(record); err != nil {
+			return exported, fmt.Errorf("cannot export key %v: %w", entry.Key, err)
+		}
+		exported++
+	}
+	return exported, nil
+}
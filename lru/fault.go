@@ -0,0 +1,64 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lru
+
+import (
+	"math/rand/v2"
+	"time"
+)
+
+// FaultInjection configures the faults injected by SetFaultInjection.
+// Rates are fractions between 0 and 1 of the affected calls.
+type FaultInjection struct {
+	// HandlerErrorRate is the fraction of handler calls that fail with ErrInjectedFault
+	// instead of running, as if the handler itself had failed
+	HandlerErrorRate float64
+
+	// LockDelayRate is the fraction of lock acquisitions held for a random time
+	// up to LockDelay before the operation runs, simulating slow operations
+	LockDelayRate float64
+	LockDelay     time.Duration
+
+	// AsyncDropRate is the fraction of asynchronous handler calls discarded
+	// as if the queue was full, counting them in Stats
+	AsyncDropRate float64
+}
+
+// SetFaultInjection makes the cache misbehave on purpose, so integration tests and soak
+// runs can validate how an application copes with failing handlers, slow operations and
+// lost notifications. It is meant for testing only. A nil configuration disables it.
+func (c *LRU[K, V, MetaT]) SetFaultInjection(faults *FaultInjection) {
+	if faults != nil {
+		copied := *faults
+		faults = &copied
+	}
+	c.faults.Store(faults)
+}
+
+// hit reports whether a fault happening with the given rate is injected now
+func (f *FaultInjection) hit(rate float64) bool {
+	return rate > 0 && rand.Float64() < rate
+}
+
+// injectLockDelay sleeps while holding the lock, when a lock delay is injected
+func (c *LRU[K, V, MetaT]) injectLockDelay() {
+	faults := c.faults.Load()
+	if faults == nil || faults.LockDelay <= 0 || !faults.hit(faults.LockDelayRate) {
+		return
+	}
+	time.Sleep(rand.N(faults.LockDelay))
+}
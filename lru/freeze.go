@@ -0,0 +1,41 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lru
+
+// Freeze makes the cache read-only. Operations that would modify the stored
+// entries are rejected with ErrFrozen until Thaw is called. Callers that prefer
+// a passthrough behavior can just ignore ErrFrozen and carry on.
+// Reads keep working and still update the recency order.
+func (c *LRU[K, V, MetaT]) Freeze() {
+	c.lock()
+	defer c.mu.Unlock()
+	c.frozen = true
+}
+
+// Thaw makes a frozen cache writable again.
+func (c *LRU[K, V, MetaT]) Thaw() {
+	c.lock()
+	defer c.mu.Unlock()
+	c.frozen = false
+}
+
+// Frozen reports whether the cache is read-only.
+func (c *LRU[K, V, MetaT]) Frozen() bool {
+	c.rlock()
+	defer c.mu.RUnlock()
+	return c.frozen
+}
@@ -0,0 +1,51 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lru
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestFrozenCacheRejectsTouchAndPin(t *testing.T) {
+	c := New[string, int](struct{}{})
+	if err := c.CreateElementWithTTL("a", 1, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	_, before, _ := c.GetWithExpiry("a")
+	c.Freeze()
+
+	if touched, err := c.Touch("a", time.Minute, false); touched || !errors.Is(err, ErrFrozen) {
+		t.Fatalf("Touch: got (%t, %v), want (false, ErrFrozen)", touched, err)
+	}
+	if pinned, err := c.Pin("a"); pinned || !errors.Is(err, ErrFrozen) {
+		t.Fatalf("Pin: got (%t, %v), want (false, ErrFrozen)", pinned, err)
+	}
+	if _, after, _ := c.GetWithExpiry("a"); !after.Equal(before) {
+		t.Fatalf("got expiration %v, want it unchanged at %v", after, before)
+	}
+	if got := c.Stats().Pinned; got != 0 {
+		t.Fatalf("got %d pinned entries, want 0", got)
+	}
+
+	c.Thaw()
+	c.Close()
+	if _, err := c.Unpin("a"); !errors.Is(err, ErrCacheClosed) {
+		t.Fatalf("Unpin: got %v, want ErrCacheClosed", err)
+	}
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lru
+
+import "container/list"
+
+// ghosts is a bounded list of recently evicted keys. Values are not retained.
+type ghosts[K comparable] struct {
+	capacity int
+	index    map[K]*list.Element
+	list     *list.List
+}
+
+// add remembers an evicted key, forgetting the oldest ones when full
+func (g *ghosts[K]) add(key K) {
+	if g.capacity <= 0 {
+		return
+	}
+
+	if element, found := g.index[key]; found {
+		g.list.MoveToFront(element)
+		return
+	}
+	g.index[key] = g.list.PushFront(key)
+
+	for g.list.Len() > g.capacity {
+		g.remove(g.list.Back().Value.(K))
+	}
+}
+
+// remove forgets a key
+func (g *ghosts[K]) remove(key K) {
+	element, found := g.index[key]
+	if !found {
+		return
+	}
+	delete(g.index, key)
+	g.list.Remove(element)
+}
+
+// contains reports whether a key was recently evicted
+func (g *ghosts[K]) contains(key K) bool {
+	_, found := g.index[key]
+	return found
+}
+
+// SetGhostCapacity sets how many recently evicted keys are remembered.
+// Values are not retained, only keys. A capacity of zero disables ghost entries.
+func (c *LRU[K, V, MetaT]) SetGhostCapacity(capacity int) {
+	c.lock()
+	defer c.mu.Unlock()
+
+	c.ghosts.capacity = capacity
+	if c.ghosts.index == nil {
+		c.ghosts.index = make(map[K]*list.Element)
+		c.ghosts.list = list.New()
+	}
+
+	for c.ghosts.list.Len() > max(capacity, 0) {
+		c.ghosts.remove(c.ghosts.list.Back().Value.(K))
+	}
+}
+
+// WasRecentlyEvicted reports whether the key was evicted recently and
+// has not been inserted again since then.
+func (c *LRU[K, V, MetaT]) WasRecentlyEvicted(key K) bool {
+	c.rlock()
+	defer c.mu.RUnlock()
+	return c.ghosts.contains(key)
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lru
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+// SetContentHasher enables content hashing. The encoder turns a value into the bytes
+// to hash, and it is called every time an entry is inserted or updated. The resulting
+// hash is stored in Entry.ContentHash, so it can be used as an ETag for conditional gets.
+// Entries already stored are hashed again. A nil encoder disables content hashing.
+func (c *LRU[K, V, MetaT]) SetContentHasher(encoder func(value V) ([]byte, error)) error {
+	c.lock()
+	defer c.mu.Unlock()
+
+	c.contentEncoder = encoder
+	for element := c.list.Front(); element != nil; element = element.Next() {
+		it := element.Value.(*item[K, V])
+		it.entry.ContentHash = ""
+
+		if encoder == nil {
+			continue
+		}
+		hash, err := c.contentHash(it.entry.Value)
+		if err != nil {
+			return fmt.Errorf("cannot hash key %v: %w", it.entry.Key, err)
+		}
+		it.entry.ContentHash = hash
+	}
+	return nil
+}
+
+// ContentHash returns the content hash of the entry associated with the given key,
+// and whether it was found, without updating its recency.
+func (c *LRU[K, V, MetaT]) ContentHash(key K) (string, bool) {
+	c.rlock()
+	defer c.mu.RUnlock()
+
+	element, found := c.index[key]
+	if !found || element.Value.(*item[K, V]).expired(time.Now()) {
+		return "", false
+	}
+	return element.Value.(*item[K, V]).entry.ContentHash, true
+}
+
+// contentHash computes the hex-encoded SHA-256 of a value
+func (c *LRU[K, V, MetaT]) contentHash(value V) (string, error) {
+	content, err := c.contentEncoder(value)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:]), nil
+}
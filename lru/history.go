@@ -0,0 +1,133 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lru
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// KeyEvent is a step of the lifecycle of a key, as recorded in a journal.
+// Consecutive reads are folded into a single JournalGet event counting them.
+type KeyEvent struct {
+	Seq      uint64    // Sequence number of the first record of the event
+	Op       JournalOp // Operation recorded
+	Updated  bool      // Whether a JournalCreate replaced a value already cached
+	Origin   string    // Where the value of a JournalCreate came from
+	TTL      string    // Ttl of a JournalCreate, empty when it never expires
+	Reason   string    // Why a JournalDelete removed the entry
+	Accesses int       // Amount of reads folded into a JournalGet
+}
+
+// String describes the event, e.g. "refreshed by loader (ttl 5m0s)" or "accessed 3 times".
+func (e KeyEvent) String() string {
+	var b strings.Builder
+	switch e.Op {
+	case JournalCreate:
+		switch {
+		case !e.Updated:
+			b.WriteString("inserted")
+		case e.Origin == OriginLoader.String():
+			b.WriteString("refreshed")
+		default:
+			b.WriteString("updated")
+		}
+		if e.Origin != "" && e.Origin != OriginInsert.String() {
+			fmt.Fprintf(&b, " by %s", e.Origin)
+		}
+		if e.TTL != "" {
+			fmt.Fprintf(&b, " (ttl %s)", e.TTL)
+		}
+	case JournalGet:
+		fmt.Fprintf(&b, "accessed %d times", e.Accesses)
+	case JournalDelete:
+		b.WriteString("deleted")
+		if e.Reason != "" && e.Reason != ReasonDeleted.String() {
+			fmt.Fprintf(&b, " (%s)", e.Reason)
+		}
+	case JournalExpire:
+		b.WriteString("expired")
+	case JournalEvict:
+		b.WriteString("evicted")
+	case JournalPurge:
+		b.WriteString("purged")
+	default:
+		b.WriteString(string(e.Op))
+	}
+	return b.String()
+}
+
+// KeyHistory reads a journal written by SetJournal and returns the lifecycle of a single key:
+// when it was inserted, refreshed and read, and why it was removed. This is usually the fastest
+// way to find out why a stale value was served. Only the last limit events are returned,
+// all of them when limit is zero or negative.
+func KeyHistory[K comparable](r io.Reader, key K, limit int) ([]KeyEvent, error) {
+	decoder := json.NewDecoder(r)
+	var events []KeyEvent
+	present := false
+
+	for read := 1; ; read++ {
+		var record JournalRecord[K, json.RawMessage]
+		if err := decoder.Decode(&record); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return events, fmt.Errorf("cannot decode journal record %d: %w", read, err)
+		}
+
+		// Purges drop every key, so they have no key of their own
+		if record.Op == JournalPurge {
+			if present {
+				events = append(events, KeyEvent{Seq: record.Seq, Op: JournalPurge})
+				present = false
+			}
+			continue
+		}
+		if record.Key != key {
+			continue
+		}
+
+		switch record.Op {
+		case JournalGet:
+			if last := len(events) - 1; last >= 0 && events[last].Op == JournalGet {
+				events[last].Accesses++
+				continue
+			}
+			events = append(events, KeyEvent{Seq: record.Seq, Op: JournalGet, Accesses: 1})
+		case JournalCreate:
+			events = append(events, KeyEvent{
+				Seq:     record.Seq,
+				Op:      JournalCreate,
+				Updated: present,
+				Origin:  record.Origin,
+				TTL:     record.TTL,
+			})
+			present = true
+		default:
+			events = append(events, KeyEvent{Seq: record.Seq, Op: record.Op, Reason: record.Reason})
+			present = false
+		}
+	}
+
+	if limit > 0 && len(events) > limit {
+		events = events[len(events)-limit:]
+	}
+	return events, nil
+}
@@ -0,0 +1,224 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lru
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// defaultProgressInterval is the amount of records imported between progress reports
+const defaultProgressInterval = 1000
+
+// ImportFormat defines the encoding of the records read by Import.
+type ImportFormat int
+
+const (
+	// ImportJSONL reads one JSON object per line, like {"key": ..., "value": ..., "ttl": "5m", "cost": 512}.
+	// Keys and values are decoded with encoding/json. The ttl and cost fields are optional.
+	ImportJSONL ImportFormat = iota
+
+	// ImportCSV reads comma-separated records. The first record is a header
+	// naming the columns, which must include "key" and "value". An optional
+	// "ttl" column can hold durations like "5m", and an optional "cost" one
+	// the cost of each entry, accounted as ElementOptions.Cost.
+	ImportCSV
+)
+
+// ImportOptions configures how records are decoded and inserted by Import.
+type ImportOptions[K comparable, V any] struct {
+	Format ImportFormat
+
+	// ParseKey and ParseValue convert raw CSV columns into keys and values.
+	// They are required for CSV and ignored for JSONL.
+	ParseKey   func(raw string) (K, error)
+	ParseValue func(raw string) (V, error)
+
+	// Progress is called with the amount of records imported so far,
+	// every ProgressInterval records and once more when the import ends
+	Progress         func(imported int)
+	ProgressInterval int
+}
+
+// importRecord is a single decoded record
+type importRecord[K comparable, V any] struct {
+	Key   K      `json:"key"`
+	Value V      `json:"value"`
+	TTL   string `json:"ttl,omitempty"`
+	Cost  int64  `json:"cost,omitempty"`
+}
+
+// Import streams the records read from r into the cache and returns how many were inserted.
+// Records are read one at a time and inserted before reading the next one,
+// so the reader is never consumed faster than the cache can absorb it.
+// Records rejected by ShouldAdmit are skipped. The import stops on the first error
+// or when the context is done.
+func (c *LRU[K, V, MetaT]) Import(ctx context.Context, r io.Reader, options ImportOptions[K, V]) (int, error) {
+	var next func() (importRecord[K, V], error)
+	var err error
+
+	switch options.Format {
+	case ImportJSONL:
+		next = newJSONLReader[K, V](r)
+	case ImportCSV:
+		next, err = newCSVReader(r, options)
+	default:
+		err = fmt.Errorf("unknown import format: %d", options.Format)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	interval := options.ProgressInterval
+	if interval <= 0 {
+		interval = defaultProgressInterval
+	}
+
+	imported, read := 0, 0
+	defer func() {
+		if options.Progress != nil {
+			options.Progress(imported)
+		}
+	}()
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return imported, err
+		}
+
+		record, err := next()
+		if errors.Is(err, io.EOF) {
+			return imported, nil
+		}
+		read++
+		if err != nil {
+			return imported, fmt.Errorf("record %d: %w", read, err)
+		}
+
+		// Records without ttl get the default one
+		var ttl time.Duration
+		if record.TTL != "" {
+			if ttl, err = time.ParseDuration(record.TTL); err != nil {
+				return imported, fmt.Errorf("record %d: cannot parse ttl: %w", read, err)
+			}
+		}
+		if record.Cost < 0 {
+			return imported, fmt.Errorf("record %d: negative cost: %d", read, record.Cost)
+		}
+
+		err = c.importLocked(record, max(ttl, 0))
+		if errors.Is(err, ErrNotAdmitted) {
+			continue
+		}
+		if err != nil {
+			return imported, fmt.Errorf("record %d: %w", read, err)
+		}
+		imported++
+
+		if options.Progress != nil && imported%interval == 0 {
+			options.Progress(imported)
+		}
+	}
+}
+
+// importLocked inserts an imported record with its ttl and cost given as element options,
+// so the handlers see the cost. Records without ttl get the default one
+func (c *LRU[K, V, MetaT]) importLocked(record importRecord[K, V], ttl time.Duration) error {
+	if err := c.lockChecked(); err != nil {
+		return err
+	}
+	defer c.mu.Unlock()
+
+	var options *ElementOptions
+	if record.TTL != "" || record.Cost > 0 {
+		if record.TTL == "" {
+			ttl = c.entryTTLUnsafe(Entry[K, V]{Key: record.Key, Value: record.Value, Origin: OriginImport})
+		}
+		options = &ElementOptions{TTL: ttl, Cost: record.Cost}
+	}
+	return c.insertUnsafe(record.Key, record.Value, options, OriginImport)
+}
+
+// newJSONLReader returns a function that decodes one JSONL record per call
+func newJSONLReader[K comparable, V any](r io.Reader) func() (importRecord[K, V], error) {
+	decoder := json.NewDecoder(r)
+	return func() (record importRecord[K, V], err error) {
+		err = decoder.Decode(&record)
+		return record, err
+	}
+}
+
+// newCSVReader reads the CSV header and returns a function that decodes one record per call
+func newCSVReader[K comparable, V any](r io.Reader, options ImportOptions[K, V]) (func() (importRecord[K, V], error), error) {
+	if options.ParseKey == nil || options.ParseValue == nil {
+		return nil, errors.New("csv import requires ParseKey and ParseValue")
+	}
+
+	reader := csv.NewReader(r)
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("cannot read csv header: %w", err)
+	}
+
+	keyColumn, valueColumn, ttlColumn, costColumn := -1, -1, -1, -1
+	for i, name := range header {
+		switch name {
+		case "key":
+			keyColumn = i
+		case "value":
+			valueColumn = i
+		case "ttl":
+			ttlColumn = i
+		case "cost":
+			costColumn = i
+		default:
+			return nil, fmt.Errorf("unknown csv column: %q", name)
+		}
+	}
+	if keyColumn < 0 || valueColumn < 0 {
+		return nil, errors.New("csv header must include key and value columns")
+	}
+
+	return func() (record importRecord[K, V], err error) {
+		fields, err := reader.Read()
+		if err != nil {
+			return record, err
+		}
+
+		if record.Key, err = options.ParseKey(fields[keyColumn]); err != nil {
+			return record, fmt.Errorf("cannot parse key: %w", err)
+		}
+		if record.Value, err = options.ParseValue(fields[valueColumn]); err != nil {
+			return record, fmt.Errorf("cannot parse value: %w", err)
+		}
+		if ttlColumn >= 0 {
+			record.TTL = fields[ttlColumn]
+		}
+		if costColumn >= 0 && fields[costColumn] != "" {
+			if record.Cost, err = strconv.ParseInt(fields[costColumn], 10, 64); err != nil {
+				return record, fmt.Errorf("cannot parse cost: %w", err)
+			}
+		}
+		return record, nil
+	}, nil
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lru
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestImportCostSeenByHandlers(t *testing.T) {
+	// The metadata keeps the accounted size, evicting above a budget of 100
+	c := New[string, string](int64(0))
+	c.OnInsert(func(size *int64, entry Entry[string, string]) error {
+		*size += entry.Cost
+		return nil
+	})
+	c.OnDelete(func(size *int64, entry Entry[string, string], _ RemovalReason) error {
+		*size -= entry.Cost
+		return nil
+	})
+	c.ShouldEvict(func(size *int64, entry Entry[string, string], _ Entry[string, string]) bool {
+		return *size+entry.Cost > 100
+	})
+
+	input := `{"key":"a","value":"1","cost":80}
+{"key":"b","value":"2","cost":80}
+{"key":"c","value":"3","cost":80}
+`
+	imported, err := c.Import(context.Background(), strings.NewReader(input), ImportOptions[string, string]{Format: ImportJSONL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if imported != 3 {
+		t.Fatalf("got %d imported, want 3", imported)
+	}
+	if got := c.SizeBytes(); got != 80 {
+		t.Fatalf("got size %d, want 80", got)
+	}
+	if got := c.Keys(); len(got) != 1 || got[0] != "c" {
+		t.Fatalf("got keys %v, want [c]", got)
+	}
+}
+
+func TestImportNumbersRecordsAfterSkipped(t *testing.T) {
+	c := New[string, string](struct{}{})
+	c.ShouldAdmit(func(_ *struct{}, entry Entry[string, string]) bool {
+		return entry.Key != "a"
+	})
+
+	input := `{"key":"a","value":"1"}
+{"key":"b","value":"2"}
+{"key":"c","value":"3","ttl":"soon"}
+`
+	imported, err := c.Import(context.Background(), strings.NewReader(input), ImportOptions[string, string]{Format: ImportJSONL})
+	if err == nil || !strings.HasPrefix(err.Error(), "record 3:") {
+		t.Fatalf("got error %v, want it about record 3", err)
+	}
+	if imported != 1 {
+		t.Fatalf("got %d imported, want 1", imported)
+	}
+}
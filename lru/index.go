@@ -0,0 +1,149 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lru
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrIndexNotFound is returned when looking up an index that was never added.
+var ErrIndexNotFound = errors.New("index not found")
+
+// valueIndex is a secondary index grouping the keys of the entries by an extracted value
+type valueIndex[K comparable, V any] struct {
+	extract func(entry Entry[K, V]) string
+	keys    map[string]map[K]struct{}
+}
+
+// AddIndex registers a secondary index called name, which groups the entries
+// by the value returned by extract, e.g. the tenant stored in the value.
+// Entries for which extract returns an empty string are not indexed.
+// The index is built from the current entries and kept up to date on every
+// insertion and removal. Adding an index with an existing name replaces it.
+// Like handlers, extract runs while the cache is locked, so it must not call any cache method.
+func (c *LRU[K, V, MetaT]) AddIndex(name string, extract func(entry Entry[K, V]) string) {
+	c.lock()
+	defer c.mu.Unlock()
+
+	if c.valueIndexes == nil {
+		c.valueIndexes = make(map[string]*valueIndex[K, V])
+	}
+	index := &valueIndex[K, V]{
+		extract: extract,
+		keys:    make(map[string]map[K]struct{}),
+	}
+	c.valueIndexes[name] = index
+
+	for element := c.list.Front(); element != nil; element = element.Next() {
+		index.add(name, element.Value.(*item[K, V]))
+	}
+}
+
+// RemoveIndex drops the secondary index called name.
+func (c *LRU[K, V, MetaT]) RemoveIndex(name string) {
+	c.lock()
+	defer c.mu.Unlock()
+
+	if _, found := c.valueIndexes[name]; !found {
+		return
+	}
+	delete(c.valueIndexes, name)
+	for element := c.list.Front(); element != nil; element = element.Next() {
+		delete(element.Value.(*item[K, V]).indexKeys, name)
+	}
+}
+
+// GetByIndex returns the values of the entries whose extracted value, for the index
+// called name, is indexKey. Like Peek, the recency of the entries is not updated.
+// Expired entries are skipped, and values are returned in no particular order.
+func (c *LRU[K, V, MetaT]) GetByIndex(name, indexKey string) ([]V, error) {
+	c.rlock()
+	defer c.mu.RUnlock()
+
+	index, found := c.valueIndexes[name]
+	if !found {
+		return nil, ErrIndexNotFound
+	}
+
+	now := time.Now()
+	keys := index.keys[indexKey]
+	values := make([]V, 0, len(keys))
+	for key := range keys {
+		element, found := c.index[key]
+		if !found {
+			continue
+		}
+		if it := element.Value.(*item[K, V]); !it.expired(now) {
+			values = append(values, it.entry.Value)
+		}
+	}
+	return values, nil
+}
+
+// indexUnsafe adds the item to every secondary index without locking the LRU
+func (c *LRU[K, V, MetaT]) indexUnsafe(it *item[K, V]) {
+	for name, index := range c.valueIndexes {
+		index.add(name, it)
+	}
+}
+
+// unindexUnsafe removes the item from every secondary index without locking the LRU
+func (c *LRU[K, V, MetaT]) unindexUnsafe(it *item[K, V]) {
+	for name, index := range c.valueIndexes {
+		index.remove(name, it)
+	}
+}
+
+// add indexes the key of the item under its extracted value, which is kept in the item,
+// so it is removed from the right group even if the value is mutated afterwards
+func (index *valueIndex[K, V]) add(name string, it *item[K, V]) {
+	indexKey := index.extract(it.entry)
+	if indexKey == "" {
+		delete(it.indexKeys, name)
+		return
+	}
+	if it.indexKeys == nil {
+		it.indexKeys = make(map[string]string)
+	}
+	it.indexKeys[name] = indexKey
+
+	keys, found := index.keys[indexKey]
+	if !found {
+		keys = make(map[K]struct{})
+		index.keys[indexKey] = keys
+	}
+	keys[it.entry.Key] = struct{}{}
+}
+
+// remove drops the key of the item from the group it was indexed under, forgetting empty groups
+func (index *valueIndex[K, V]) remove(name string, it *item[K, V]) {
+	indexKey, indexed := it.indexKeys[name]
+	if !indexed {
+		return
+	}
+	delete(it.indexKeys, name)
+
+	keys, found := index.keys[indexKey]
+	if !found {
+		return
+	}
+	delete(keys, it.entry.Key)
+	if len(keys) == 0 {
+		delete(index.keys, indexKey)
+	}
+}
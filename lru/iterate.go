@@ -0,0 +1,55 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lru
+
+import "time"
+
+// Keys returns all the keys ordered from the most to the least recently used.
+func (c *LRU[K, V, MetaT]) Keys() []K {
+	c.rlock()
+	defer c.mu.RUnlock()
+
+	now := time.Now()
+	keys := make([]K, 0, c.list.Len())
+	for element := c.list.Front(); element != nil; element = element.Next() {
+		it := element.Value.(*item[K, V])
+		if !it.expired(now) {
+			keys = append(keys, it.entry.Key)
+		}
+	}
+	return keys
+}
+
+// Range calls fn for every entry, from the most to the least recently used,
+// stopping as soon as fn returns false. The cache is read-locked during the
+// whole traversal, so fn must not call any other cache method.
+// The operations modifying the cache fail with ErrReentrantMutation when called from fn.
+func (c *LRU[K, V, MetaT]) Range(fn func(entry Entry[K, V]) bool) {
+	c.rlock()
+	defer c.mu.RUnlock()
+
+	now := time.Now()
+	for element := c.list.Front(); element != nil; element = element.Next() {
+		it := element.Value.(*item[K, V])
+		if it.expired(now) {
+			continue
+		}
+		if !c.visitUnsafe(fn, it.entry) {
+			return
+		}
+	}
+}
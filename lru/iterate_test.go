@@ -0,0 +1,86 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lru
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestRangeRejectsMutations(t *testing.T) {
+	c := New[string, int](struct{}{})
+	c.CreateElement("a", 1)
+	c.CreateElement("b", 2)
+
+	var errs []error
+	c.Range(func(entry Entry[string, int]) bool {
+		errs = append(errs, c.DeleteElement(entry.Key))
+		return true
+	})
+	if len(errs) != 2 {
+		t.Fatalf("got %d calls, want 2", len(errs))
+	}
+	for _, err := range errs {
+		if !errors.Is(err, ErrReentrantMutation) {
+			t.Fatalf("got error %v, want ErrReentrantMutation", err)
+		}
+	}
+
+	// Mutations work again once the iteration is over
+	if err := c.DeleteElement("a"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestExportRejectsMutations(t *testing.T) {
+	c := New[string, int](struct{}{})
+	c.CreateElement("a", 1)
+
+	var err error
+	exported, exportErr := c.Export(&bytes.Buffer{}, func(entry Entry[string, int]) bool {
+		err = c.CreateElement("b", 2)
+		return true
+	})
+	if exportErr != nil || exported != 1 {
+		t.Fatalf("got (%d, %v), want 1 exported", exported, exportErr)
+	}
+	if !errors.Is(err, ErrReentrantMutation) {
+		t.Fatalf("got error %v, want ErrReentrantMutation", err)
+	}
+}
+
+func TestDeleteIfRejectsMutations(t *testing.T) {
+	c := New[string, int](struct{}{})
+	c.CreateElement("a", 1)
+	c.CreateElement("b", 2)
+
+	var err error
+	deleted, deleteErr := c.DeleteIf(func(entry Entry[string, int]) bool {
+		err = c.CreateElement("c", 3)
+		return entry.Value == 1
+	})
+	if deleteErr != nil || deleted != 1 {
+		t.Fatalf("got (%d, %v), want 1 deleted", deleted, deleteErr)
+	}
+	if !errors.Is(err, ErrReentrantMutation) {
+		t.Fatalf("got error %v, want ErrReentrantMutation", err)
+	}
+	if c.Contains("a") || c.Contains("c") || !c.Contains("b") {
+		t.Fatalf("got keys %v, want only b", c.Keys())
+	}
+}
@@ -0,0 +1,198 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lru
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// JournalOp is the kind of operation stored in a journal record.
+type JournalOp string
+
+const (
+	JournalCreate JournalOp = "create" // An entry was inserted or updated
+	JournalGet    JournalOp = "get"    // An entry was read, updating its recency
+	JournalDelete JournalOp = "delete" // An entry was deleted
+	JournalExpire JournalOp = "expire" // An expired entry was removed
+	JournalEvict  JournalOp = "evict"  // An entry was evicted to make room for another one
+	JournalPurge  JournalOp = "purge"  // Every entry was dropped without running handlers
+)
+
+// JournalRecord is a single operation recorded in the journal.
+// Value, TTL and Origin are only set for JournalCreate records,
+// and Reason for the JournalDelete ones.
+type JournalRecord[K comparable, V any] struct {
+	Seq    uint64    `json:"seq"`
+	Op     JournalOp `json:"op"`
+	Key    K         `json:"key"`
+	Value  V         `json:"value,omitempty"`
+	TTL    string    `json:"ttl,omitempty"`
+	Origin string    `json:"origin,omitempty"`
+	Reason string    `json:"reason,omitempty"`
+}
+
+// journal writes the operations performed on the cache as JSONL
+type journal struct {
+	encoder *json.Encoder
+	seq     uint64
+}
+
+// SetJournal records every operation that changes the content or the order of the cache
+// to w, as JSONL records numbered with consecutive sequence numbers.
+// The journal can be replayed against a fresh cache with Replay to reproduce
+// its state, which is handy for bug reports. Pass a nil writer to stop journaling.
+// Records are written under the cache lock, so w should be buffered.
+// When a record cannot be written, the operation that produced it returns the error.
+func (c *LRU[K, V, MetaT]) SetJournal(w io.Writer) {
+	c.lock()
+	defer c.mu.Unlock()
+
+	if w == nil {
+		c.journal = nil
+		return
+	}
+	c.journal = &journal{encoder: json.NewEncoder(w)}
+}
+
+// journalUnsafe writes a record to the journal, if any, without locking the LRU
+func (c *LRU[K, V, MetaT]) journalUnsafe(op JournalOp, entry Entry[K, V], ttl time.Duration) error {
+	if c.journal == nil {
+		return nil
+	}
+
+	record := JournalRecord[K, V]{Op: op, Key: entry.Key, Value: entry.Value}
+	if op == JournalCreate {
+		record.Origin = entry.Origin.String()
+	}
+	if ttl > 0 {
+		record.TTL = ttl.String()
+	}
+	return c.writeJournalUnsafe(record)
+}
+
+// journalRemovalUnsafe writes the record of a removal to the journal, if any,
+// without locking the LRU
+func (c *LRU[K, V, MetaT]) journalRemovalUnsafe(key K, reason RemovalReason) error {
+	if c.journal == nil {
+		return nil
+	}
+
+	record := JournalRecord[K, V]{Op: reason.journalOp(), Key: key}
+	if record.Op == JournalDelete {
+		record.Reason = reason.String()
+	}
+	return c.writeJournalUnsafe(record)
+}
+
+// writeJournalUnsafe numbers a record and writes it to the journal without locking the LRU
+func (c *LRU[K, V, MetaT]) writeJournalUnsafe(record JournalRecord[K, V]) error {
+	c.journal.seq++
+	record.Seq = c.journal.seq
+	if err := c.journal.encoder.Encode(record); err != nil {
+		return fmt.Errorf("cannot journal %s of key %v: %w", record.Op, record.Key, err)
+	}
+	return nil
+}
+
+// journalOp returns the journal operation recording a removal for the reason
+func (r RemovalReason) journalOp() JournalOp {
+	switch r {
+	case ReasonEvicted:
+		return JournalEvict
+	case ReasonExpired:
+		return JournalExpire
+	default:
+		return JournalDelete
+	}
+}
+
+// Replay applies the records of a journal written by SetJournal to the cache,
+// and returns how many were applied. The cache should be fresh and configured
+// like the journaled one to reproduce its state. Evictions are journaled before
+// the insertion that caused them, and replayed as plain removals.
+// When step is not nil, it is called after applying each record, so the state
+// of the cache can be inspected step by step. Returning an error stops the replay.
+func (c *LRU[K, V, MetaT]) Replay(r io.Reader, step func(record JournalRecord[K, V]) error) (int, error) {
+	decoder := json.NewDecoder(r)
+	applied := 0
+	var last uint64
+
+	for {
+		var record JournalRecord[K, V]
+		if err := decoder.Decode(&record); err != nil {
+			if errors.Is(err, io.EOF) {
+				return applied, nil
+			}
+			return applied, fmt.Errorf("cannot decode journal record %d: %w", applied+1, err)
+		}
+		if record.Seq <= last {
+			return applied, fmt.Errorf("journal record %d is out of sequence after %d", record.Seq, last)
+		}
+		last = record.Seq
+
+		if err := c.replayRecord(record); err != nil {
+			return applied, fmt.Errorf("cannot replay journal record %d: %w", record.Seq, err)
+		}
+		applied++
+
+		if step != nil {
+			if err := step(record); err != nil {
+				return applied, err
+			}
+		}
+	}
+}
+
+// replayRecord applies a single journal record to the cache
+func (c *LRU[K, V, MetaT]) replayRecord(record JournalRecord[K, V]) error {
+	if err := c.lockChecked(); err != nil {
+		return err
+	}
+	defer c.mu.Unlock()
+
+	switch record.Op {
+	case JournalCreate:
+		options := &ElementOptions{}
+		if record.TTL != "" {
+			ttl, err := time.ParseDuration(record.TTL)
+			if err != nil {
+				return fmt.Errorf("invalid ttl %q: %w", record.TTL, err)
+			}
+			options.TTL = ttl
+		}
+		return c.insertUnsafe(record.Key, record.Value, options, OriginReplay)
+	case JournalGet:
+		_, _, err := c.getElementUnsafe(record.Key)
+		return err
+	case JournalDelete:
+		return c.deleteElementUnsafe(record.Key)
+	case JournalExpire:
+		_, err := c.expireElementUnsafe(record.Key)
+		return err
+	case JournalEvict:
+		_, err := c.removeElementUnsafe(record.Key, ReasonEvicted)
+		return err
+	case JournalPurge:
+		return c.purgeUnsafe()
+	default:
+		return fmt.Errorf("unknown operation %q", record.Op)
+	}
+}
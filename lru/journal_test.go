@@ -0,0 +1,72 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lru
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestJournalReplayRoundTrip(t *testing.T) {
+	var journal bytes.Buffer
+	c := NewWithCapacity[string, int](2, struct{}{})
+	c.SetJournal(&journal)
+
+	steps := []func() error{
+		func() error { return c.CreateElement("a", 1) },
+		func() error { return c.CreateElement("b", 2) },
+		func() error { _, err := c.GetElement("a"); return err },
+		func() error { return c.CreateElement("c", 3) }, // Evicts b
+		func() error { return c.DeleteElement("a") },
+		func() error { return c.CreateElementWithTTL("d", 4, time.Hour) },
+	}
+	for i, step := range steps {
+		if err := step(); err != nil {
+			t.Fatalf("step %d: %v", i, err)
+		}
+	}
+
+	replayed := NewWithCapacity[string, int](2, struct{}{})
+	var ops []JournalOp
+	applied, err := replayed.Replay(&journal, func(record JournalRecord[string, int]) error {
+		ops = append(ops, record.Op)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []JournalOp{JournalCreate, JournalCreate, JournalGet, JournalEvict, JournalCreate, JournalDelete, JournalCreate}
+	if applied != len(want) || !slices.Equal(ops, want) {
+		t.Fatalf("got %d records %v, want %v", applied, ops, want)
+	}
+	if got, want := replayed.Keys(), c.Keys(); !slices.Equal(got, want) {
+		t.Fatalf("got keys %v, want %v", got, want)
+	}
+	for _, key := range c.Keys() {
+		got, _ := replayed.Peek(key)
+		want, _ := c.Peek(key)
+		if got != want {
+			t.Fatalf("got %d for %s, want %d", got, key, want)
+		}
+	}
+	if _, expiresAt, _ := replayed.GetWithExpiry("d"); expiresAt.IsZero() {
+		t.Fatal("got d without expiration, want its ttl replayed")
+	}
+}
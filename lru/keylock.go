@@ -0,0 +1,80 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lru
+
+import "sync"
+
+// keyLock is the lock guarding a single key.
+// It is dropped from the cache once nobody holds or waits for it.
+type keyLock struct {
+	mu   sync.Mutex
+	refs int // Goroutines holding or waiting for the lock
+}
+
+// LockKey acquires the lock of the given key, blocking until it is available.
+// While a key is locked, or someone is waiting for it, its entry is never
+// chosen for eviction. This allows callers mutating the resource an entry points
+// to (a file, a database row...) to serialize with the eviction of that entry.
+// The key does not need to be present in the cache.
+// Every call to LockKey must be paired with a call to UnlockKey.
+func (c *LRU[K, V, MetaT]) LockKey(key K) {
+	c.lock()
+	if c.keyLocks == nil {
+		c.keyLocks = make(map[K]*keyLock)
+	}
+	kl, found := c.keyLocks[key]
+	if !found {
+		kl = &keyLock{}
+		c.keyLocks[key] = kl
+	}
+	kl.refs++
+	c.mu.Unlock()
+
+	kl.mu.Lock()
+}
+
+// UnlockKey releases the lock of the given key.
+// It panics when the key is not locked.
+func (c *LRU[K, V, MetaT]) UnlockKey(key K) {
+	c.lock()
+	kl, found := c.keyLocks[key]
+	if !found {
+		c.mu.Unlock()
+		panic("lru: unlock of unlocked key")
+	}
+	kl.refs--
+	if kl.refs == 0 {
+		delete(c.keyLocks, key)
+	}
+	c.mu.Unlock()
+
+	kl.mu.Unlock()
+}
+
+// WithKeyLock runs fn while holding the lock of the given key.
+// The cache itself is not locked, so fn can freely use it.
+func (c *LRU[K, V, MetaT]) WithKeyLock(key K, fn func() error) error {
+	c.LockKey(key)
+	defer c.UnlockKey(key)
+	return fn()
+}
+
+// keyLockedUnsafe reports whether the given key is locked, or about to be, without locking the cache.
+func (c *LRU[K, V, MetaT]) keyLockedUnsafe(key K) bool {
+	_, locked := c.keyLocks[key]
+	return locked
+}
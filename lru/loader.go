@@ -0,0 +1,167 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lru
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"time"
+)
+
+// call is a loader execution shared by every GetOrCreate waiting for the same key
+type call[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+
+	options *ElementOptions // Settings of the inserted entry, nil for the default ones
+	refresh bool            // Whether the call reloads an entry already admitted
+	early   bool            // Whether the call recomputes an entry still valid, see SetEarlyExpiration
+
+	// Entry reloaded by a refresh, only replaced if it is still stored when the call ends
+	element  *list.Element
+	storedAt time.Time
+}
+
+// GetOrCreate returns the value associated with the given key. When the key is missing,
+// the loader is called and its result is inserted and returned.
+// Concurrent calls for the same missing key are coalesced: only one loader runs,
+// and the other callers wait for its result. The loader runs without holding the
+// cache lock, so other keys are not blocked while it works.
+// Values are not inserted when the loader fails, nor when they are rejected by
+// the load time admission. The callers waiting for a loader that panics get ErrLoaderPanicked,
+// while the panic goes on in the caller that ran it. When the loader fails and the entry expired recently,
+// within its grace period, the stale value is returned instead of the error.
+// The loader is always called while the bypass mode set with SetBypass is enabled.
+func (c *LRU[K, V, MetaT]) GetOrCreate(key K, loader func() (V, error)) (V, error) {
+	return c.getOrCreate(context.Background(), key, func(context.Context) (V, error) {
+		return loader()
+	})
+}
+
+// getOrCreate implements GetOrCreate and GetOrCreateCtx.
+// Waiting callers give up when their context is done, the loader keeps running for the others.
+func (c *LRU[K, V, MetaT]) getOrCreate(ctx context.Context, key K, loader func(ctx context.Context) (V, error)) (V, error) {
+	if err := c.lockChecked(); err != nil {
+		var zero V
+		return zero, err
+	}
+
+	if c.bypass {
+		c.bypassUnsafe(key)
+		c.mu.Unlock()
+		return loader(ctx)
+	}
+
+	restore := c.bindContextUnsafe(ctx)
+	value, found, err := c.getElementUnsafe(key)
+	restore()
+	if err != nil || found && !c.expiresEarlyUnsafe(key) {
+		c.mu.Unlock()
+		return value, err
+	}
+
+	// Wait for the loader already running for this key, if any.
+	// Early recomputations do not wait, the current value is still valid
+	if inflight, running := c.calls[key]; running {
+		c.mu.Unlock()
+		if found {
+			return value, nil
+		}
+		select {
+		case <-inflight.done:
+			return inflight.value, inflight.err
+		case <-ctx.Done():
+			var zero V
+			return zero, ctx.Err()
+		}
+	}
+
+	if c.calls == nil {
+		c.calls = make(map[K]*call[V])
+	}
+	current := &call[V]{done: make(chan struct{}), early: found}
+	c.calls[key] = current
+	c.mu.Unlock()
+
+	c.load(ctx, key, current, loader)
+	return current.value, current.err
+}
+
+// load runs the loader for a call and inserts its result, releasing the waiting callers.
+// The context is passed to the loader, and to the handlers run by the insertion.
+func (c *LRU[K, V, MetaT]) load(ctx context.Context, key K, current *call[V], loader func(ctx context.Context) (V, error)) {
+	current.err = ErrLoaderPanicked
+	defer close(current.done)
+
+	var elapsed time.Duration
+
+	defer func() {
+		c.lock()
+		defer c.mu.Unlock()
+		defer c.bindContextUnsafe(ctx)()
+
+		// Refreshes don't bring back the entries removed or overwritten during the reload
+		delete(c.calls, key)
+		insert := c.admitLoadUnsafe(elapsed)
+		if current.refresh {
+			insert = c.reloadedUnsafe(key, current)
+		}
+		if current.err == nil && insert {
+			current.err = c.insertUnsafe(key, current.value, current.options, OriginLoader)
+			if errors.Is(current.err, ErrNotAdmitted) || errors.Is(current.err, ErrNilValue) {
+				// The value is still returned to the callers, it is just not cached
+				current.err = nil
+			}
+			if element, found := c.index[key]; found && current.err == nil {
+				element.Value.(*item[K, V]).loadTime = elapsed
+			}
+		}
+
+		// Serve the stale value, if any, when the loader fails
+		if current.err != nil {
+			if value, found := c.fallbackValueUnsafe(key, current.early); found {
+				current.value, current.err = value, nil
+			}
+		}
+	}()
+
+	start := time.Now()
+	current.value, current.err = loader(ctx)
+	elapsed = time.Since(start)
+}
+
+// fallbackValueUnsafe returns the value served when a loader fails, without locking the LRU:
+// the one of an expired entry within its grace period or, for early recomputations,
+// the one of the entry while it is still valid
+func (c *LRU[K, V, MetaT]) fallbackValueUnsafe(key K, early bool) (V, bool) {
+	if element, found := c.index[key]; found && early {
+		if it := element.Value.(*item[K, V]); !it.expired(time.Now()) {
+			return it.entry.Value, true
+		}
+	}
+	return c.staleValueUnsafe(key)
+}
+
+// reloadedUnsafe reports whether the entry reloaded by a call is still the stored one,
+// neither removed nor overwritten since the call started, without locking the LRU
+func (c *LRU[K, V, MetaT]) reloadedUnsafe(key K, current *call[V]) bool {
+	element, found := c.index[key]
+	return found && element == current.element &&
+		element.Value.(*item[K, V]).entry.StoredAt.Equal(current.storedAt)
+}
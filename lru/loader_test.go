@@ -0,0 +1,84 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lru
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestEarlyRecomputationFailureServesCurrentValue(t *testing.T) {
+	c := New[string, int](struct{}{})
+	c.SetDefaultTTL(time.Hour)
+	c.SetEarlyExpiration(1e9)
+
+	_, err := c.GetOrCreate("k", func() (int, error) {
+		time.Sleep(time.Millisecond) // The load time drives the early recomputations
+		return 1, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	value, err := c.GetOrCreate("k", func() (int, error) {
+		return 0, errors.New("backend down")
+	})
+	if err != nil || value != 1 {
+		t.Fatalf("got (%d, %v), want the current value 1", value, err)
+	}
+}
+
+func TestGetOrCreateCoalescesLoads(t *testing.T) {
+	c := New[string, int](struct{}{})
+	started, release := make(chan struct{}), make(chan struct{})
+	var loads atomic.Int32
+	loader := func() (int, error) {
+		if loads.Add(1) == 1 {
+			close(started)
+		}
+		<-release
+		return 1, nil
+	}
+
+	var wg sync.WaitGroup
+	values := make([]int, 8)
+	errs := make([]error, len(values))
+	for i := range values {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			values[i], errs[i] = c.GetOrCreate("k", loader)
+		}()
+		if i == 0 {
+			<-started
+		}
+	}
+	close(release)
+	wg.Wait()
+
+	if got := loads.Load(); got != 1 {
+		t.Fatalf("got %d loads, want 1", got)
+	}
+	for i := range values {
+		if errs[i] != nil || values[i] != 1 {
+			t.Fatalf("caller %d got (%d, %v), want (1, nil)", i, values[i], errs[i])
+		}
+	}
+}
@@ -18,156 +18,886 @@ package lru
 
 import (
 	"container/list"
+	"context"
 	"errors"
+	"fmt"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
+// ErrLockBusy is returned by the Try* operations when the cache lock
+// could not be acquired immediately.
+var ErrLockBusy = errors.New("cache lock is busy")
+
+// ErrFrozen is returned by the operations that modify the cache while it is frozen.
+var ErrFrozen = errors.New("cache is frozen")
+
+// ErrNotComparable is returned by the compare-and-swap operations when the values
+// cannot be compared with ==.
+var ErrNotComparable = errors.New("values are not comparable")
+
+// ErrReentrantMutation is returned by the operations modifying the cache when they are called
+// from inside an iteration callback, like the ones passed to Range or Export.
+// The iteration holds the cache lock, so they would deadlock otherwise. As goroutines
+// cannot be told apart, the ones calling them while a callback runs get it too.
+var ErrReentrantMutation = errors.New("cache modified from inside an iteration callback")
+
+// ErrNotAdmitted is returned by the insertions of new entries rejected by the ShouldAdmit handler.
+var ErrNotAdmitted = errors.New("entry not admitted")
+
+// ErrDependencyCycle is returned by AddDependency when the dependency would create a cycle.
+var ErrDependencyCycle = errors.New("dependency cycle")
+
+// ErrCascadeFailed is returned, joined with the handler errors, when an entry was removed
+// but some of the entries depending on it could not be deleted. The operation itself succeeded.
+var ErrCascadeFailed = errors.New("cascade failed")
+
+// ErrNilValue is returned by the insertions of nil values when the NilReject policy is set.
+var ErrNilValue = errors.New("nil value")
+
+// ErrNotFound is returned by the lookups of keys that are not present, or expired.
+var ErrNotFound = errors.New("key not found")
+
+// ErrEvictionStalled is returned by the insertions that need room when no entry can be evicted,
+// because all of them are pinned, locked or vetoed by the SelectVictim or CanEvict handlers.
+var ErrEvictionStalled = errors.New("eviction stalled")
+
+// ErrValueTooLarge is returned by the insertions of entries that do not fit
+// even after evicting every other entry.
+var ErrValueTooLarge = errors.New("value too large")
+
+// ErrEvictionBudgetExceeded is returned by the insertions that would evict more entries
+// than allowed by SetEvictionBudget.
+var ErrEvictionBudgetExceeded = errors.New("eviction budget exceeded")
+
+// ErrCacheClosed is returned by the operations that modify the cache after Close.
+var ErrCacheClosed = errors.New("cache is closed")
+
+// ErrLoaderPanicked is returned to the callers waiting for a loader that panicked.
+var ErrLoaderPanicked = errors.New("loader panicked")
+
+// ErrHandlerFailed matches, with errors.Is, every HandlerError.
+var ErrHandlerFailed = errors.New("handler failed")
+
+// HandlerError wraps the error returned by a user-defined handler,
+// telling which hook failed and for which key.
+type HandlerError struct {
+	Hook string // Name of the hook, like "OnDelete"
+	Key  any
+	Err  error
+}
+
+func (e *HandlerError) Error() string {
+	return fmt.Sprintf("%s handler failed for key %v: %v", e.Hook, e.Key, e.Err)
+}
+
+func (e *HandlerError) Unwrap() error { return e.Err }
+
+// Is makes every HandlerError match ErrHandlerFailed.
+func (e *HandlerError) Is(target error) bool { return target == ErrHandlerFailed }
+
+// ErrInjectedFault is returned by the handler calls failed on purpose by SetFaultInjection.
+var ErrInjectedFault = errors.New("injected fault")
+
+// ErrHandlerPanicked is wrapped by the HandlerError reporting a recovered handler panic.
+var ErrHandlerPanicked = errors.New("handler panicked")
+
+// handlerGuard holds the settings applied to every handler call, captured under the lock
+type handlerGuard struct {
+	onPanic   func(err error) // Receives the recovered panics, nil when they are not recovered
+	faults    *FaultInjection // Faults to inject, nil when disabled
+	timeout   time.Duration   // Time waited for each call, zero waits forever
+	onOverrun func(err error) // Receives the calls that exceeded the timeout
+	overruns  *atomic.Uint64  // Counts the calls that exceeded the timeout
+}
+
+// handlerGuardUnsafe returns the current handler settings without locking the LRU
+func (c *LRU[K, V, MetaT]) handlerGuardUnsafe() handlerGuard {
+	return handlerGuard{
+		onPanic:   c.onHandlerPanic,
+		faults:    c.faults.Load(),
+		timeout:   c.handlerTimeout,
+		onOverrun: c.onHandlerOverrun,
+		overruns:  &c.counters.handlerTimeouts,
+	}
+}
+
+// runHandler calls a handler, wrapping the error it returns, if any, into a HandlerError.
+// When the guard has a panic callback, panics are recovered, reported to it and returned as errors.
+// When it has a timeout, the handler is abandoned once it expires.
+func runHandler[K comparable](hook string, key K, guard handlerGuard, call func() error) error {
+	if guard.timeout > 0 {
+		return runHandlerWithTimeout(hook, key, guard, call)
+	}
+	return callHandler(hook, key, guard, call)
+}
+
+// callHandler calls a handler applying the panic and fault settings of the guard
+func callHandler[K comparable](hook string, key K, guard handlerGuard, call func() error) (err error) {
+	if guard.onPanic != nil {
+		defer func() {
+			if r := recover(); r != nil {
+				err = &HandlerError{Hook: hook, Key: key, Err: fmt.Errorf("%w: %v", ErrHandlerPanicked, r)}
+				guard.onPanic(err)
+			}
+		}()
+	}
+
+	if guard.faults != nil && guard.faults.hit(guard.faults.HandlerErrorRate) {
+		return &HandlerError{Hook: hook, Key: key, Err: ErrInjectedFault}
+	}
+
+	if err := call(); err != nil {
+		return &HandlerError{Hook: hook, Key: key, Err: err}
+	}
+	return nil
+}
+
 // Entry represents a key-value pair stored in the cache.
 // It is passed to the user-defined handlers.
-type Entry struct {
-	Key   string
-	Value any
+type Entry[K comparable, V any] struct {
+	Key   K
+	Value V
+
+	// ContentHash is the hex-encoded SHA-256 of the value.
+	// It is only computed when a content encoder is set with SetContentHasher.
+	ContentHash string
+
+	// Origin tells where the value came from, and StoredAt when it was stored
+	Origin   Origin
+	StoredAt time.Time
+
+	// Cost is the one accounted in SizeBytes: the one given with ElementOptions.Cost,
+	// or the one returned by the function set with SetCostFunc. Zero without cost tracking.
+	Cost int64
+
+	ctx context.Context // Context of the operation running a handler, see Context
+}
+
+// item is the internal representation of an entry stored in the list
+type item[K comparable, V any] struct {
+	entry         Entry[K, V]
+	probation     bool      // Whether the item belongs to the probation segment
+	referenced    bool      // Whether the item was accessed since it was last considered for eviction
+	pinned        bool      // Whether the item is protected from eviction
+	priority      int       // Items with lower priorities are evicted first
+	fixedCost     int64     // Cost given with the element options, zero when measured by the cost function
+	expiresAt     time.Time // Zero when the item never expires
+	ttl           time.Duration
+	ttlDeadline   time.Time // Deadline set by the ttl, the idle timeout can make it expire earlier
+	grace         time.Duration
+	softTTL       time.Duration
+	softExpiresAt time.Time         // Moment the item becomes eligible for a background reload, zero if never
+	loadTime      time.Duration     // How long the loader took to produce the value
+	heapIndex     int               // Position in the expirations heap, -1 when not there
+	indexKeys     map[string]string // Value extracted for each secondary index, by index name
 }
 
 // LRU implements a thread-safe LRU cache with support for
 // user-defined handlers and custom metadata.
-type LRU[MetaT any] struct {
+type LRU[K comparable, V any, MetaT any] struct {
 	mu       sync.RWMutex
-	index    map[string]*list.Element
+	index    map[K]*list.Element
 	list     *list.List
 	Metadata MetaT // User-defined metadata available in all handlers
+	capacity int   // Maximum amount of entries, zero means unbounded
+
+	secondChance        bool
+	frozen              bool
+	closed              bool
+	nilPolicy           NilPolicy
+	pinnedNeverExpire   bool
+	transactionalInsert bool
+	bypass              bool // Whether GetOrCreate goes straight to the loader
+	evictionBudget      int  // Maximum entries evicted by a single insertion, zero means unbounded
+	onBudgetExceeded    func(entry Entry[K, V], evicted int)
+
+	calls     map[K]*call[V] // Loaders running in GetOrCreate
+	loadTimes *loadTimes
+
+	defaultTTL  time.Duration
+	ttlFunc     func(entry Entry[K, V]) time.Duration
+	idleTimeout time.Duration
+	gracePeriod time.Duration
+	ttlJitter   float64
+
+	expirations expirations[K, V]
+	scheduler   Scheduler[K]
+	janitorStop chan struct{}
+	janitorDone chan struct{}
+
+	earlyExpirationBeta float64
+	refreshRatio        float64
+	refreshLoader       func(key K) (V, error)
+
+	keyDecorator   func(ctx context.Context, key K) K
+	opCtx          context.Context // Context of the operation holding the lock, see bindContextUnsafe
+	contentEncoder func(value V) ([]byte, error)
+	costFunc       func(entry Entry[K, V]) int64
+	size           int64 // Sum of the costs of all the entries
+
+	lockStats lockStats
+	rollups   rollups                        // Time-bucketed counters, updated under the write lock
+	faults    atomic.Pointer[FaultInjection] // Read before locking, see SetFaultInjection
+	counters  counters
+	ghosts    ghosts[K]
+	keyLocks  map[K]*keyLock // Keys locked with LockKey
+
+	iterating   atomic.Int32 // Iteration callbacks running, see lockChecked
+	handlerPool *handlerPool // Runs handlers outside the lock, nil when they run synchronously
+	journal     *journal
+
+	valueIndexes map[string]*valueIndex[K, V] // Secondary indexes added with AddIndex
+	dependents   map[K]map[K]struct{}         // Keys depending on each key
+	dependencies map[K]map[K]struct{}         // Keys each key depends on
+
+	priorities     map[int]int // Amount of entries of each priority, zero excepted
+	levels         []int       // Keys of priorities, sorted
+	prioritized    int         // Entries whose priority is not zero
+	insertPosition InsertPosition
+	probationHead  *list.Element // Most recently used element of the probation segment
+	probationLen   int
 
 	// User-defined hooks
-	onInsertHandler    func(metadata *MetaT, entry Entry) error
-	onDeleteHandler    func(metadata *MetaT, entry Entry) error
-	onAccessHandler    func(metadata *MetaT, entry Entry) error
-	shouldEvictHandler func(metadata *MetaT, entry Entry) bool
+	onInsertHandler     func(metadata *MetaT, entry Entry[K, V]) error
+	onDeleteHandler     func(metadata *MetaT, entry Entry[K, V], reason RemovalReason) error
+	onAccessHandler     func(metadata *MetaT, entry Entry[K, V]) error
+	onExpireHandler     func(metadata *MetaT, entry Entry[K, V]) error
+	onSoftExpireHandler func(metadata *MetaT, entry Entry[K, V]) error
+	shouldEvictHandler  func(metadata *MetaT, entry Entry[K, V], candidate Entry[K, V]) bool
+	shouldAdmitHandler  func(metadata *MetaT, entry Entry[K, V]) bool
+	canEvictHandler     func(metadata *MetaT, entry Entry[K, V]) bool
+	selectVictimHandler func(metadata *MetaT, candidates []Entry[K, V]) int
+	victimCandidates    int
+	onCascadeHandler    func(metadata *MetaT, parent K, entry Entry[K, V]) error
+	onHandlerPanic      func(err error)
+	onHandlerOverrun    func(err error)
+	handlerTimeout      time.Duration
 }
 
 // New creates a new LRU structure. The `metadata` object can be any value,
 // and is accessible in all handler functions.
-func New[MetaT any](metadata MetaT) *LRU[MetaT] {
-	return &LRU[MetaT]{
-		index:    make(map[string]*list.Element),
+// Keys and values types must be provided explicitly, e.g. New[string, int](metadata)
+func New[K comparable, V any, MetaT any](metadata MetaT) *LRU[K, V, MetaT] {
+	return &LRU[K, V, MetaT]{
+		index:    make(map[K]*list.Element),
 		list:     list.New(),
 		Metadata: metadata,
 	}
 }
 
+// NewWithCapacity creates a new LRU structure that keeps at most `capacity` entries,
+// evicting the least recently used ones automatically when full.
+// Handlers can still be defined for advanced cases, ShouldEvict included.
+func NewWithCapacity[K comparable, V any, MetaT any](capacity int, metadata MetaT) *LRU[K, V, MetaT] {
+	c := New[K, V](metadata)
+	c.capacity = capacity
+	return c
+}
+
 // OnInsert sets a handler to be called when a new entry is created
-func (c *LRU[MetaT]) OnInsert(handler func(metadata *MetaT, entry Entry) error) {
+func (c *LRU[K, V, MetaT]) OnInsert(handler func(metadata *MetaT, entry Entry[K, V]) error) {
 	c.onInsertHandler = handler
 }
 
-// OnDelete sets a handler to be called when an entry is removed from the cache.
-func (c *LRU[MetaT]) OnDelete(handler func(metadata *MetaT, entry Entry) error) {
+// OnDelete sets a handler to be called when an entry is removed from the cache,
+// or when its value is replaced by a new one. The reason tells the cause apart,
+// e.g. to delete the files backing the entries only when they are evicted.
+func (c *LRU[K, V, MetaT]) OnDelete(handler func(metadata *MetaT, entry Entry[K, V], reason RemovalReason) error) {
 	c.onDeleteHandler = handler
 }
 
 // OnAccess sets a handler to be called when an entry is accessed.
-func (c *LRU[MetaT]) OnAccess(handler func(metadata *MetaT, entry Entry) error) {
+func (c *LRU[K, V, MetaT]) OnAccess(handler func(metadata *MetaT, entry Entry[K, V]) error) {
 	c.onAccessHandler = handler
 }
 
-// ShouldEvict sets a handler that decides whether eviction should occur.
+// OnExpire sets a handler to be called when an expired entry is removed from the cache.
+// When defined, it runs instead of OnDelete for expired entries, so it must also
+// update any metadata that OnDelete maintains.
+func (c *LRU[K, V, MetaT]) OnExpire(handler func(metadata *MetaT, entry Entry[K, V]) error) {
+	c.onExpireHandler = handler
+}
+
+// OnSoftExpire sets a handler to be called when an entry past its soft TTL is accessed,
+// right before it is reloaded in the background.
+func (c *LRU[K, V, MetaT]) OnSoftExpire(handler func(metadata *MetaT, entry Entry[K, V]) error) {
+	c.onSoftExpireHandler = handler
+}
+
+// ShouldEvict sets a handler that decides whether eviction should occur before inserting entry.
 // It should return true if the cache should evict the least recently used entry.
-func (c *LRU[MetaT]) ShouldEvict(handler func(metadata *MetaT, entry Entry) bool) {
+// The candidate is the entry eviction would consider first, i.e. the least recently used
+// evictable one of the lowest priority, or the zero Entry when there is none. It may still
+// get a second chance, be vetoed by CanEvict, or be passed over by the SelectVictim handler.
+func (c *LRU[K, V, MetaT]) ShouldEvict(handler func(metadata *MetaT, entry Entry[K, V], candidate Entry[K, V]) bool) {
 	c.shouldEvictHandler = handler
 }
 
+// ShouldAdmit sets a handler that decides whether a new entry is inserted at all,
+// so values too large or unlikely to be reused do not evict useful ones.
+// It runs before the eviction loop, and rejected insertions fail with ErrNotAdmitted.
+// Updates of existing entries are not checked.
+func (c *LRU[K, V, MetaT]) ShouldAdmit(handler func(metadata *MetaT, entry Entry[K, V]) bool) {
+	c.shouldAdmitHandler = handler
+}
+
+// SetSecondChance enables or disables the second-chance mechanism.
+// When enabled, an entry accessed since it was last considered for eviction
+// is moved to the front once instead of being evicted.
+func (c *LRU[K, V, MetaT]) SetSecondChance(enabled bool) {
+	c.lock()
+	defer c.mu.Unlock()
+
+	c.secondChance = enabled
+	if !enabled {
+		for element := c.list.Front(); element != nil; element = element.Next() {
+			element.Value.(*item[K, V]).referenced = false
+		}
+	}
+}
+
+// ElementOptions holds the per-entry settings used by CreateElementWithOptions.
+type ElementOptions struct {
+	// TTL is the time after which the entry expires. Zero means it never expires
+	TTL time.Duration
+
+	// Grace is how long an expired entry can still be served as stale.
+	// Zero means the grace period of the cache is used
+	Grace time.Duration
+
+	// Priority decides the eviction order across entries: the ones with lower
+	// priorities are evicted first, even if they were used more recently.
+	// Entries with the same priority are evicted in the usual order. Defaults to zero
+	Priority int
+
+	// SoftTTL is the time after which the entry is reloaded in the background
+	// on its next access, while it is still served. It requires a loader set
+	// with SetRefreshAhead, and should be shorter than TTL. Zero disables it
+	SoftTTL time.Duration
+
+	// Cost is accounted in SizeBytes for the entry instead of the one returned
+	// by the function set with SetCostFunc. Zero means the entry is measured as usual
+	Cost int64
+}
+
+// keepOptions makes insertUnsafe only replace the value of an existing entry,
+// keeping its ttl, deadlines, grace, priority and cost. New entries get the default options.
+var keepOptions = &ElementOptions{}
+
 // CreateElement inserts or updates an entry in the cache.
 // If eviction is needed, the least recently used entries are removed
 // before the new one is inserted.
 // Eviction conditions are managed by the user defining OnEvict
-func (c *LRU[MetaT]) CreateElement(key string, value any) error {
-	c.mu.Lock()
+func (c *LRU[K, V, MetaT]) CreateElement(key K, value V) error {
+	if err := c.lockChecked(); err != nil {
+		return err
+	}
+	defer c.mu.Unlock()
+	return c.createElementUnsafe(key, value, nil)
+}
+
+// TryCreateElement behaves like CreateElement but fails fast with ErrLockBusy
+// instead of blocking when the cache lock is held by another operation.
+func (c *LRU[K, V, MetaT]) TryCreateElement(key K, value V) error {
+	if !c.tryLock() {
+		return ErrLockBusy
+	}
 	defer c.mu.Unlock()
+	return c.createElementUnsafe(key, value, nil)
+}
+
+// createElementUnsafe inserts or updates an entry in the cache without locking it.
+// When options are nil, the ttl is computed with the TTL function or taken from the default one.
+func (c *LRU[K, V, MetaT]) createElementUnsafe(key K, value V, options *ElementOptions) error {
+	return c.insertUnsafe(key, value, options, OriginInsert)
+}
+
+// insertUnsafe inserts or updates an entry whose value came from the given origin,
+// without locking the LRU
+func (c *LRU[K, V, MetaT]) insertUnsafe(key K, value V, options *ElementOptions, origin Origin) error {
+	if err := c.writableUnsafe(); err != nil {
+		return err
+	}
+
+	if c.nilPolicy != NilAllow && isNil(value) {
+		if c.nilPolicy == NilDelete {
+			return c.deleteElementUnsafe(key)
+		}
+		return ErrNilValue
+	}
+
+	entry := Entry[K, V]{Key: key, Value: value, Origin: origin, StoredAt: time.Now()}
+	if c.contentEncoder != nil {
+		hash, err := c.contentHash(value)
+		if err != nil {
+			return err
+		}
+		entry.ContentHash = hash
+	}
 
-	entry := Entry{Key: key, Value: value}
 	element, exists := c.index[key]
 
+	// The cost is known before the handlers run, so they can account it
+	var fixedCost int64
+	switch {
+	case options == keepOptions && exists:
+		fixedCost = element.Value.(*item[K, V]).fixedCost
+	case options != nil && options != keepOptions:
+		fixedCost = max(options.Cost, 0)
+	}
+	entry.Cost = c.costUnsafe(entry, fixedCost)
+
+	var evictionErr error
 	if exists {
-		// Update existing element
-		element.Value = entry
+		// Update existing element, the replaced value is removed
+		old := element.Value.(*item[K, V]).entry
+		if err := c.runOnDeleteUnsafe(old, ReasonReplaced); err != nil {
+			return err
+		}
+		c.unindexUnsafe(element.Value.(*item[K, V]))
+		element.Value.(*item[K, V]).fixedCost = fixedCost
+		c.setEntryUnsafe(element.Value.(*item[K, V]), entry)
 	} else {
-		// Run eviction loop before inserting new element
-		for c.shouldEvictHandler != nil && c.shouldEvictHandler(&c.Metadata, entry) {
-			if err := c.deleteLastElementUnsafe(); err != nil {
-				return err
+		if c.shouldAdmitHandler != nil && !c.shouldAdmitHandler(&c.Metadata, entry) {
+			return ErrNotAdmitted
+		}
+
+		// Run eviction loop before inserting new element, within the eviction budget.
+		// Errors found once an entry is evicted, like failed cascades, don't stop the insertion,
+		// they are returned at the end
+		for evicted := 0; c.needsEvictionUnsafe(entry); evicted++ {
+			if err := c.checkEvictionBudgetUnsafe(entry, evicted); err != nil {
+				return errors.Join(err, evictionErr)
+			}
+			_, removed, err := c.deleteLastElementUnsafe()
+			if !removed {
+				return errors.Join(err, evictionErr)
 			}
+			evictionErr = errors.Join(evictionErr, err)
 		}
-		// Insert new element at the front
-		element = c.list.PushFront(entry)
+		// Insert new element at the configured position
+		c.ghosts.remove(key)
+		element = c.pushUnsafe(entry, fixedCost)
 		c.index[key] = element
 	}
+	it := element.Value.(*item[K, V])
+	switch {
+	case options != keepOptions:
+		c.applyOptionsUnsafe(it, options)
+	case !exists:
+		c.applyOptionsUnsafe(it, nil)
+	}
 
-	// Run create handler if present
-	if c.onInsertHandler != nil {
-		return c.onInsertHandler(&c.Metadata, entry)
+	// Run create handler if present, undoing the insertion when it fails in transactional mode.
+	// The insertion is journaled once it is settled, so the journal matches the handlers
+	if err := c.runOnInsertUnsafe(entry); err != nil {
+		if c.transactionalInsert {
+			return errors.Join(err, c.rollbackInsertUnsafe(element, exists), evictionErr)
+		}
+		return errors.Join(err, c.journalUnsafe(JournalCreate, entry, it.ttl), evictionErr)
 	}
-	return nil
+	return errors.Join(c.journalUnsafe(JournalCreate, entry, it.ttl), evictionErr)
 }
 
 // GetElement returns the value associated with the given key and
-// moves it to the front (most recently used).
-func (c *LRU[MetaT]) GetElement(key string) (any, error) {
-	c.mu.Lock()
+// moves it to the front (most recently used). ErrNotFound is returned on a miss.
+func (c *LRU[K, V, MetaT]) GetElement(key K) (V, error) {
+	value, found, err := c.getElement(key)
+	return value, notFound(found, err)
+}
+
+// TryGetElement behaves like GetElement but fails fast with ErrLockBusy
+// instead of blocking when the cache lock is held by another operation.
+func (c *LRU[K, V, MetaT]) TryGetElement(key K) (V, error) {
+	if !c.tryLock() {
+		var zero V
+		return zero, ErrLockBusy
+	}
+	defer c.mu.Unlock()
+	value, found, err := c.getElementUnsafe(key)
+	return value, notFound(found, err)
+}
+
+// notFound turns a miss into ErrNotFound, unless there is another error to report
+func notFound(found bool, err error) error {
+	if err == nil && !found {
+		return ErrNotFound
+	}
+	return err
+}
+
+// getElement returns the value associated with the given key, and whether it was found
+func (c *LRU[K, V, MetaT]) getElement(key K) (V, bool, error) {
+	if err := c.lockChecked(); err != nil {
+		var zero V
+		return zero, false, err
+	}
 	defer c.mu.Unlock()
+	return c.getElementUnsafe(key)
+}
+
+// getElementUnsafe returns the value associated with the given key, and whether it was found,
+// without locking the LRU
+func (c *LRU[K, V, MetaT]) getElementUnsafe(key K) (value V, found bool, err error) {
+	defer func() { c.recordLookupUnsafe(found) }()
 
 	element, found := c.index[key]
 	if !found {
-		return nil, nil
+		if c.ghosts.contains(key) {
+			c.counters.ghostHits.Add(1)
+		}
+		return value, false, nil
 	}
 
+	// Expired entries are reported as missing, and removed once their grace period is over
+	now := time.Now()
+	if it := element.Value.(*item[K, V]); it.expired(now) {
+		if it.removable(now) {
+			_, err := c.expireElementUnsafe(key)
+			return value, false, err
+		}
+		return value, false, nil
+	}
+
+	value, err = c.accessElementUnsafe(element, false)
+	if refreshErr := c.refreshAheadUnsafe(element.Value.(*item[K, V]), now); err == nil {
+		err = refreshErr
+	}
+	if err == nil {
+		err = c.journalUnsafe(JournalGet, Entry[K, V]{Key: key}, 0)
+	}
+	return value, true, err
+}
+
+// accessElementUnsafe marks an element as accessed, moving it to the front
+// and running the OnAccess handler, without locking the LRU.
+// Stale reads do not slide the idle deadline, so they cannot revive expired entries
+func (c *LRU[K, V, MetaT]) accessElementUnsafe(element *list.Element, stale bool) (V, error) {
 	// Move to front (recent use)
-	c.list.MoveToFront(element)
-	entry := element.Value.(Entry)
+	c.promoteUnsafe(element)
+	it := element.Value.(*item[K, V])
+	it.referenced = c.secondChance
+	if c.idleTimeout > 0 && !stale {
+		c.refreshDeadlineUnsafe(it)
+	}
+	entry := it.entry
 
 	// Run get handler if present
-	if c.onAccessHandler != nil {
-		if err := c.onAccessHandler(&c.Metadata, entry); err != nil {
-			return nil, err
-		}
+	if err := c.runOnAccessUnsafe(entry); err != nil {
+		var zero V
+		return zero, err
 	}
 	return entry.Value, nil
 }
 
+// Peek returns the value associated with the given key, and whether it was found,
+// without updating its recency nor running the OnAccess handler.
+func (c *LRU[K, V, MetaT]) Peek(key K) (V, bool) {
+	c.rlock()
+	defer c.mu.RUnlock()
+	return c.peekUnsafe(key)
+}
+
+// Contains reports whether the key is present in the cache
+// without updating its recency nor running any handler.
+func (c *LRU[K, V, MetaT]) Contains(key K) bool {
+	c.rlock()
+	defer c.mu.RUnlock()
+
+	element, found := c.index[key]
+	return found && !element.Value.(*item[K, V]).expired(time.Now())
+}
+
 // DeleteElement removes an entry by key from the LRU.
-func (c *LRU[MetaT]) DeleteElement(key string) error {
-	c.mu.Lock()
+func (c *LRU[K, V, MetaT]) DeleteElement(key K) error {
+	if err := c.lockChecked(); err != nil {
+		return err
+	}
 	defer c.mu.Unlock()
+
+	if err := c.writableUnsafe(); err != nil {
+		return err
+	}
 	return c.deleteElementUnsafe(key)
 }
 
+// Pop removes the entry associated with the given key and returns its value,
+// and whether it was found, in a single atomic step: when several goroutines
+// pop the same key, only one of them gets the value. OnDelete is fired as usual.
+// Expired entries are considered missing.
+func (c *LRU[K, V, MetaT]) Pop(key K) (V, bool, error) {
+	var zero V
+	if err := c.lockChecked(); err != nil {
+		return zero, false, err
+	}
+	defer c.mu.Unlock()
+
+	if err := c.writableUnsafe(); err != nil {
+		return zero, false, err
+	}
+
+	value, found := c.peekUnsafe(key)
+	if !found {
+		return zero, false, nil
+	}
+	if err := c.deleteElementUnsafe(key); err != nil {
+		return zero, false, err
+	}
+	return value, true, nil
+}
+
+// RemoveOldest evicts the entry the eviction loop would evict next, usually the least recently
+// used one, and returns it, so the cache can be shrunk on external signals like memory pressure.
+// It follows the same rules as the eviction loop: priorities, second chances, pinned and
+// vetoed entries, and the SelectVictim handler. OnDelete is fired with ReasonEvicted.
+// The returned bool is false when the cache is empty, and ErrEvictionStalled is returned
+// when no entry can be evicted. Evicted entries are still returned along with the errors found
+// afterwards, like ErrCascadeFailed or the ones writing the journal.
+func (c *LRU[K, V, MetaT]) RemoveOldest() (Entry[K, V], bool, error) {
+	if err := c.lockChecked(); err != nil {
+		return Entry[K, V]{}, false, err
+	}
+	defer c.mu.Unlock()
+
+	if err := c.writableUnsafe(); err != nil {
+		return Entry[K, V]{}, false, err
+	}
+	if c.list.Len() == 0 {
+		return Entry[K, V]{}, false, nil
+	}
+
+	entry, removed, err := c.deleteLastElementUnsafe()
+	if !removed {
+		return Entry[K, V]{}, false, err
+	}
+	return entry, true, err
+}
+
+// Purge removes all the entries from the cache, running the OnDelete handler for each of them.
+// It stops at the first handler error, leaving the remaining entries in place.
+// Errors found once an entry is removed, like failed cascades, don't stop it: they are
+// joined and returned at the end.
+func (c *LRU[K, V, MetaT]) Purge() error {
+	if err := c.lockChecked(); err != nil {
+		return err
+	}
+	defer c.mu.Unlock()
+
+	if err := c.writableUnsafe(); err != nil {
+		return err
+	}
+
+	var removalErr error
+	for element := c.list.Back(); element != nil; element = c.list.Back() {
+		removed, err := c.removeElementUnsafe(element.Value.(*item[K, V]).entry.Key, ReasonPurged)
+		if !removed {
+			return errors.Join(err, removalErr)
+		}
+		removalErr = errors.Join(removalErr, err)
+	}
+	return removalErr
+}
+
+// PurgeWithoutHandlers removes all the entries from the cache at once, without running
+// any handler. Metadata updated by the handlers is not updated, so it must be reset by the caller.
+func (c *LRU[K, V, MetaT]) PurgeWithoutHandlers() error {
+	if err := c.lockChecked(); err != nil {
+		return err
+	}
+	defer c.mu.Unlock()
+
+	if err := c.writableUnsafe(); err != nil {
+		return err
+	}
+	return c.purgeUnsafe()
+}
+
+// purgeUnsafe drops every entry from the LRU without running handlers nor locking it
+func (c *LRU[K, V, MetaT]) purgeUnsafe() error {
+	for _, index := range c.valueIndexes {
+		clear(index.keys)
+	}
+	clear(c.dependents)
+	clear(c.dependencies)
+	c.counters.pinned.Store(0)
+	clear(c.priorities)
+	c.levels = c.levels[:0]
+	c.prioritized = 0
+	clear(c.index)
+	c.list.Init()
+	c.probationHead = nil
+	c.probationLen = 0
+	c.size = 0
+	for _, it := range c.expirations {
+		c.cancelScheduleUnsafe(it.entry.Key)
+	}
+	clear(c.expirations)
+	c.expirations = c.expirations[:0]
+	return c.journalUnsafe(JournalPurge, Entry[K, V]{}, 0)
+}
+
 // deleteElementUnsafe removes an entry by key from the LRU without locking it
-func (c *LRU[MetaT]) deleteElementUnsafe(key string) error {
+func (c *LRU[K, V, MetaT]) deleteElementUnsafe(key K) error {
+	_, err := c.removeElementUnsafe(key, ReasonDeleted)
+	return err
+}
 
+// expireElementUnsafe removes an expired entry by key from the LRU without locking it,
+// and reports whether it was removed, like removeElementUnsafe.
+// The OnExpire handler runs instead of OnDelete when it is defined.
+func (c *LRU[K, V, MetaT]) expireElementUnsafe(key K) (bool, error) {
+	removed, err := c.removeElementUnsafe(key, ReasonExpired)
+	if !removed {
+		return false, err
+	}
+	c.counters.expirations.Add(1)
+	c.rollups.record(time.Now(), func(b *rollupBucket) { b.expirations++ })
+	return true, err
+}
+
+// removeElementUnsafe removes an entry by key from the LRU for the given reason,
+// recording the removal in the journal, without locking it. It reports whether the entry
+// was removed, as errors found afterwards, writing the journal or cascading, don't undo it.
+func (c *LRU[K, V, MetaT]) removeElementUnsafe(key K, reason RemovalReason) (bool, error) {
 	element, found := c.index[key]
 	if !found {
-		return nil
+		return false, nil
 	}
 
-	entry := element.Value.(Entry)
+	entry := c.handlerEntryUnsafe(element.Value.(*item[K, V]).entry)
 
-	// Run delete handler if present
-	if c.onDeleteHandler != nil {
-		if err := c.onDeleteHandler(&c.Metadata, entry); err != nil {
-			return err
-		}
+	// Run delete handler if present, expired entries run the expire one instead when defined
+	var err error
+	if reason == ReasonExpired && c.onExpireHandler != nil {
+		err = runHandler("OnExpire", key, c.handlerGuardUnsafe(), func() error {
+			return c.onExpireHandler(&c.Metadata, entry)
+		})
+	} else {
+		err = c.runOnDeleteUnsafe(entry, reason)
+	}
+	if err != nil {
+		return false, err
 	}
 
 	// Remove from map and list
 	delete(c.index, key)
-	c.list.Remove(element)
-	return nil
+	c.removeUnsafe(element)
+	return true, errors.Join(c.journalRemovalUnsafe(key, reason), c.cascadeUnsafe(key))
 }
 
-// deleteLastElement removes the least recently used element from the LRU without locking it
-func (c *LRU[MetaT]) deleteLastElementUnsafe() error {
-	element := c.list.Back()
+// deleteLastElementUnsafe removes the least recently used element from the LRU, and returns
+// its entry and whether it was removed, without locking it.
+// Entries accessed since they were last considered get a second chance:
+// they are moved to the front instead, and their access bit is cleared.
+// Entries with lower priorities go first, and pinned entries, the ones whose key
+// is locked with LockKey, and the ones vetoed by CanEvict are skipped. The SelectVictim handler, when set, has the last word.
+func (c *LRU[K, V, MetaT]) deleteLastElementUnsafe() (Entry[K, V], bool, error) {
+	if c.list.Len() == 0 {
+		return Entry[K, V]{}, false, ErrValueTooLarge
+	}
+
+	var element *list.Element
+	for priority := range c.priorityLevelsUnsafe() {
+		if c.selectVictimHandler != nil {
+			element = c.selectVictimUnsafe(priority)
+		} else {
+			element = c.victimUnsafe(priority)
+		}
+		if element != nil {
+			break
+		}
+	}
 	if element == nil {
-		return errors.New("cannot evict: cache is empty")
+		return Entry[K, V]{}, false, ErrEvictionStalled
+	}
+	entry := element.Value.(*item[K, V]).entry
+	removed, err := c.removeElementUnsafe(entry.Key, ReasonEvicted)
+	if !removed {
+		return Entry[K, V]{}, false, err
+	}
+
+	c.counters.evictions.Add(1)
+	c.rollups.record(time.Now(), func(b *rollupBucket) { b.evictions++ })
+	c.ghosts.add(entry.Key)
+	return entry, true, err
+}
+
+// victimUnsafe walks the list from the back looking for the element of the given priority to evict,
+// without locking it. It returns nil when no element can be evicted.
+func (c *LRU[K, V, MetaT]) victimUnsafe(priority int) *list.Element {
+	promoted := false
+	element := c.list.Back()
+	for element != nil {
+		it := element.Value.(*item[K, V])
+		prev := element.Prev()
+
+		switch {
+		case !c.evictableUnsafe(it, priority):
+		case it.referenced:
+			it.referenced = false
+			c.promoteUnsafe(element)
+			promoted = true
+		case c.canEvictUnsafe(it):
+			return element
+		}
+		element = prev
+
+		// Promoted entries lost their access bit, so they are now candidates too
+		if element == nil && promoted {
+			promoted = false
+			element = c.list.Back()
+		}
 	}
-	entry := element.Value.(Entry)
-	return c.deleteElementUnsafe(entry.Key)
+	return nil
+}
+
+// needsEvictionUnsafe decides whether an entry must be evicted before inserting a new one
+// without locking the LRU
+func (c *LRU[K, V, MetaT]) needsEvictionUnsafe(entry Entry[K, V]) bool {
+	if c.capacity > 0 && c.list.Len() >= c.capacity {
+		return true
+	}
+	return c.shouldEvictHandler != nil && c.shouldEvictHandler(&c.Metadata, entry, c.candidateUnsafe())
+}
+
+// pushUnsafe inserts a new entry in the list at the configured position without locking it
+func (c *LRU[K, V, MetaT]) pushUnsafe(entry Entry[K, V], fixedCost int64) *list.Element {
+	it := &item[K, V]{heapIndex: -1, fixedCost: fixedCost}
+	c.setEntryUnsafe(it, entry)
+
+	var element *list.Element
+	switch c.insertPosition {
+	case InsertAtMiddle:
+		element = c.pushProbationUnsafe(it)
+	case InsertAtBack:
+		element = c.list.PushBack(it)
+	default:
+		element = c.list.PushFront(it)
+	}
+
+	c.balanceProbationUnsafe()
+	return element
+}
+
+// promoteUnsafe moves an element to the front of the list without locking it
+func (c *LRU[K, V, MetaT]) promoteUnsafe(element *list.Element) {
+	c.leaveProbationUnsafe(element)
+	c.list.MoveToFront(element)
+	c.balanceProbationUnsafe()
+}
+
+// removeUnsafe removes an element from the list without locking it
+func (c *LRU[K, V, MetaT]) removeUnsafe(element *list.Element) {
+	c.size -= element.Value.(*item[K, V]).entry.Cost
+	if element.Value.(*item[K, V]).pinned {
+		c.counters.pinned.Add(-1)
+	}
+	c.forgetPriorityUnsafe(element.Value.(*item[K, V]))
+	c.unindexUnsafe(element.Value.(*item[K, V]))
+	c.forgetDeadlineUnsafe(element.Value.(*item[K, V]))
+	c.leaveProbationUnsafe(element)
+	c.list.Remove(element)
+	c.balanceProbationUnsafe()
 }
@@ -0,0 +1,45 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lru
+
+import (
+	"path"
+	"regexp"
+)
+
+// DeleteMatching removes every entry whose key matches the given glob pattern,
+// and returns how many entries were removed, e.g. lru.DeleteMatching(cache, "/images/*").
+// Patterns follow the syntax of path.Match, so "*" does not match "/".
+// path.ErrBadPattern is returned for malformed patterns, and nothing is removed.
+// It behaves like DeleteIf otherwise.
+func DeleteMatching[K ~string, V any, MetaT any](c *LRU[K, V, MetaT], pattern string) (int, error) {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return 0, err
+	}
+	return c.DeleteIf(func(entry Entry[K, V]) bool {
+		matched, _ := path.Match(pattern, string(entry.Key))
+		return matched
+	})
+}
+
+// DeleteMatchingRegexp removes every entry whose key matches the given regular expression,
+// and returns how many entries were removed. It behaves like DeleteIf otherwise.
+func DeleteMatchingRegexp[K ~string, V any, MetaT any](c *LRU[K, V, MetaT], expr *regexp.Regexp) (int, error) {
+	return c.DeleteIf(func(entry Entry[K, V]) bool {
+		return expr.MatchString(string(entry.Key))
+	})
+}
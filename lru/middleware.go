@@ -0,0 +1,180 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lru
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Cache is the set of operations shared by the caches of this package and their views,
+// like LRU, Scope, Shadow or Decorated, so cross-cutting concerns can be layered onto
+// any of them with a Middleware.
+type Cache[K comparable, V any] interface {
+	GetElement(key K) (V, error)
+	CreateElement(key K, value V) error
+	DeleteElement(key K) error
+}
+
+// Middleware wraps a Cache adding some behaviour to its operations.
+type Middleware[K comparable, V any] func(next Cache[K, V]) Cache[K, V]
+
+// CacheOp names the operation of a Cache seen by a middleware.
+type CacheOp string
+
+const (
+	OpGet    CacheOp = "get"
+	OpCreate CacheOp = "create"
+	OpDelete CacheOp = "delete"
+)
+
+// Chain wraps the cache with the given middlewares. The first one is the outermost,
+// so it sees every operation before the others do.
+func Chain[K comparable, V any](cache Cache[K, V], middlewares ...Middleware[K, V]) Cache[K, V] {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		cache = middlewares[i](cache)
+	}
+	return cache
+}
+
+// cacheFuncs implements a Cache with a function for each operation
+type cacheFuncs[K comparable, V any] struct {
+	get    func(key K) (V, error)
+	create func(key K, value V) error
+	delete func(key K) error
+}
+
+func (f cacheFuncs[K, V]) GetElement(key K) (V, error)        { return f.get(key) }
+func (f cacheFuncs[K, V]) CreateElement(key K, value V) error { return f.create(key, value) }
+func (f cacheFuncs[K, V]) DeleteElement(key K) error          { return f.delete(key) }
+
+// observed wraps every operation of a cache with a function called
+// before it runs, which returns the function called with its result
+func observed[K comparable, V any](next Cache[K, V], start func(op CacheOp, key K) func(err error)) Cache[K, V] {
+	return cacheFuncs[K, V]{
+		get: func(key K) (V, error) {
+			end := start(OpGet, key)
+			value, err := next.GetElement(key)
+			end(err)
+			return value, err
+		},
+		create: func(key K, value V) error {
+			end := start(OpCreate, key)
+			err := next.CreateElement(key, value)
+			end(err)
+			return err
+		},
+		delete: func(key K) error {
+			end := start(OpDelete, key)
+			err := next.DeleteElement(key)
+			end(err)
+			return err
+		},
+	}
+}
+
+// WithMetrics reports the duration and the result of every operation to observe,
+// so they can be exported to any metrics system. Misses are reported with ErrNotFound.
+func WithMetrics[K comparable, V any](observe func(op CacheOp, elapsed time.Duration, err error)) Middleware[K, V] {
+	return func(next Cache[K, V]) Cache[K, V] {
+		return observed(next, func(op CacheOp, _ K) func(err error) {
+			start := time.Now()
+			return func(err error) { observe(op, time.Since(start), err) }
+		})
+	}
+}
+
+// WithLogging logs every operation to logger: the successful ones, and the misses,
+// at debug level, and the failed ones at error level.
+func WithLogging[K comparable, V any](logger *slog.Logger) Middleware[K, V] {
+	return func(next Cache[K, V]) Cache[K, V] {
+		return observed(next, func(op CacheOp, key K) func(err error) {
+			start := time.Now()
+			return func(err error) {
+				level := slog.LevelDebug
+				if err != nil && !errors.Is(err, ErrNotFound) {
+					level = slog.LevelError
+				}
+				logger.Log(context.Background(), level, "cache operation",
+					"op", op, "key", key, "elapsed", time.Since(start), "error", err)
+			}
+		})
+	}
+}
+
+// WithTracing calls start before every operation, and the function it returns
+// with the result of the operation, so spans can be created with any tracing library.
+func WithTracing[K comparable, V any](start func(op CacheOp, key K) (end func(err error))) Middleware[K, V] {
+	return func(next Cache[K, V]) Cache[K, V] {
+		return observed(next, start)
+	}
+}
+
+// WithPrefix prepends prefix to every key, so several namespaces can share a cache
+// without their keys colliding.
+func WithPrefix[K ~string, V any](prefix string) Middleware[K, V] {
+	return func(next Cache[K, V]) Cache[K, V] {
+		return cacheFuncs[K, V]{
+			get:    func(key K) (V, error) { return next.GetElement(K(prefix) + key) },
+			create: func(key K, value V) error { return next.CreateElement(K(prefix)+key, value) },
+			delete: func(key K) error { return next.DeleteElement(K(prefix) + key) },
+		}
+	}
+}
+
+// flight is a GetElement shared by every caller asking for the same key
+type flight[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+// WithSingleflight coalesces the concurrent reads of the same key, so only one of them
+// reaches the wrapped cache and the others share its result. It pays off when the
+// wrapped cache is slow, e.g. when it is layered onto a remote one.
+// When the read panics, the callers waiting for it get ErrLoaderPanicked.
+func WithSingleflight[K comparable, V any]() Middleware[K, V] {
+	return func(next Cache[K, V]) Cache[K, V] {
+		var mu sync.Mutex
+		flights := make(map[K]*flight[V])
+
+		get := func(key K) (V, error) {
+			mu.Lock()
+			if current, running := flights[key]; running {
+				mu.Unlock()
+				<-current.done
+				return current.value, current.err
+			}
+			current := &flight[V]{done: make(chan struct{}), err: ErrLoaderPanicked}
+			flights[key] = current
+			mu.Unlock()
+
+			defer func() {
+				mu.Lock()
+				delete(flights, key)
+				mu.Unlock()
+				close(current.done)
+			}()
+			current.value, current.err = next.GetElement(key)
+			return current.value, current.err
+		}
+		return cacheFuncs[K, V]{get: get, create: next.CreateElement, delete: next.DeleteElement}
+	}
+}
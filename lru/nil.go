@@ -0,0 +1,56 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lru
+
+import "reflect"
+
+// NilPolicy defines how the cache handles nil values, like nil pointers, maps or slices.
+type NilPolicy int
+
+const (
+	// NilAllow stores nil values like any other value. This is the default
+	NilAllow NilPolicy = iota
+
+	// NilReject rejects the insertion of nil values with ErrNilValue
+	NilReject
+
+	// NilDelete treats the insertion of a nil value as the deletion of its key
+	NilDelete
+)
+
+// SetNilPolicy sets how nil values are handled on insertion.
+// Rejecting them keeps handlers from receiving values they cannot type-assert.
+// Values produced by loaders are still returned when they are rejected, they are just not cached.
+func (c *LRU[K, V, MetaT]) SetNilPolicy(policy NilPolicy) {
+	c.lock()
+	defer c.mu.Unlock()
+	c.nilPolicy = policy
+}
+
+// isNil reports whether a value is nil. Values of types that cannot be nil never are.
+func isNil[V any](value V) bool {
+	v := reflect.ValueOf(any(value))
+	if !v.IsValid() {
+		return true
+	}
+
+	switch v.Kind() {
+	case reflect.Chan, reflect.Func, reflect.Interface, reflect.Map, reflect.Pointer, reflect.Slice, reflect.UnsafePointer:
+		return v.IsNil()
+	}
+	return false
+}
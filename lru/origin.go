@@ -0,0 +1,68 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lru
+
+// Origin tells where the value of an entry came from.
+type Origin int
+
+const (
+	OriginInsert Origin = iota // Inserted directly, e.g. with CreateElement
+	OriginLoader               // Produced by a loader, in GetOrCreate or a refresh
+	OriginWarmup               // Loaded by WarmConcurrently
+	OriginImport               // Restored by Import
+	OriginReplay               // Applied by Replay
+)
+
+// String returns the name of the origin.
+func (o Origin) String() string {
+	switch o {
+	case OriginInsert:
+		return "insert"
+	case OriginLoader:
+		return "loader"
+	case OriginWarmup:
+		return "warmup"
+	case OriginImport:
+		return "import"
+	case OriginReplay:
+		return "replay"
+	default:
+		return "unknown"
+	}
+}
+
+// PeekEntry returns the entry associated with the given key, and whether it was found,
+// without updating its recency. Its Origin and StoredAt fields tell where its value
+// came from and when, which helps finding out why a stale value is still cached.
+func (c *LRU[K, V, MetaT]) PeekEntry(key K) (Entry[K, V], bool) {
+	c.rlock()
+	defer c.mu.RUnlock()
+
+	if _, found := c.peekUnsafe(key); !found {
+		return Entry[K, V]{}, false
+	}
+	return c.index[key].Value.(*item[K, V]).entry, true
+}
+
+// insertLocked inserts or updates an entry whose value came from the given origin
+func (c *LRU[K, V, MetaT]) insertLocked(key K, value V, options *ElementOptions, origin Origin) error {
+	if err := c.lockChecked(); err != nil {
+		return err
+	}
+	defer c.mu.Unlock()
+	return c.insertUnsafe(key, value, options, origin)
+}
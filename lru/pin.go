@@ -0,0 +1,80 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lru
+
+// Pin protects the entry associated with the given key from eviction,
+// for entries that must stay hot, like configuration blobs.
+// Pinned entries still expire, unless SetPinnedNeverExpire is enabled,
+// and are still removed by deletions. It reports whether the key was found.
+// Pinning an entry is not a way to bypass the capacity: when every entry is pinned,
+// insertions that need to evict fail. Frozen or closed caches reject it.
+func (c *LRU[K, V, MetaT]) Pin(key K) (bool, error) {
+	return c.setPinned(key, true)
+}
+
+// Unpin makes the entry associated with the given key evictable again,
+// and reports whether the key was found. Frozen or closed caches reject it.
+func (c *LRU[K, V, MetaT]) Unpin(key K) (bool, error) {
+	return c.setPinned(key, false)
+}
+
+// SetPinnedNeverExpire makes pinned entries ignore their TTL and idle timeout
+// while they are pinned. Their deadline is restored when they are unpinned.
+func (c *LRU[K, V, MetaT]) SetPinnedNeverExpire(enabled bool) {
+	c.lock()
+	defer c.mu.Unlock()
+
+	c.pinnedNeverExpire = enabled
+	for element := c.list.Front(); element != nil; element = element.Next() {
+		if it := element.Value.(*item[K, V]); it.pinned {
+			c.refreshDeadlineUnsafe(it)
+		}
+	}
+}
+
+// setPinned pins or unpins the entry associated with the given key
+func (c *LRU[K, V, MetaT]) setPinned(key K, pinned bool) (bool, error) {
+	if err := c.lockChecked(); err != nil {
+		return false, err
+	}
+	defer c.mu.Unlock()
+
+	if err := c.writableUnsafe(); err != nil {
+		return false, err
+	}
+
+	element, found := c.index[key]
+	if !found {
+		return false, nil
+	}
+
+	it := element.Value.(*item[K, V])
+	if it.pinned == pinned {
+		return true, nil
+	}
+
+	it.pinned = pinned
+	if pinned {
+		c.counters.pinned.Add(1)
+	} else {
+		c.counters.pinned.Add(-1)
+	}
+	if c.pinnedNeverExpire {
+		c.refreshDeadlineUnsafe(it)
+	}
+	return true, nil
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lru
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Stage is a reversible transformation of the packed form of a value,
+// like compression or encryption.
+type Stage struct {
+	Apply   func(data []byte) ([]byte, error) // Runs on insert
+	Reverse func(data []byte) ([]byte, error) // Runs on get, undoing Apply
+}
+
+// Pipeline stores values in a cache of byte slices, packing them on insert and
+// unpacking them on get: values are encoded, then transformed by every stage in order
+// (e.g. compressed, then encrypted), and the stages are reversed in the opposite order.
+// Several pipelines with different stages can share the same cache, so raw hot values
+// and packed cold ones can live together as long as their keys do not collide.
+type Pipeline[K comparable, V any, MetaT any] struct {
+	cache  *LRU[K, []byte, MetaT]
+	encode func(value V) ([]byte, error)
+	decode func(data []byte) (V, error)
+	stages []Stage
+}
+
+// NewPipeline creates a new Pipeline storing its values in cache.
+// encode and decode convert values from and to bytes, e.g. with encoding/json.
+func NewPipeline[K comparable, V any, MetaT any](cache *LRU[K, []byte, MetaT], encode func(value V) ([]byte, error), decode func(data []byte) (V, error), stages ...Stage) *Pipeline[K, V, MetaT] {
+	return &Pipeline[K, V, MetaT]{
+		cache:  cache,
+		encode: encode,
+		decode: decode,
+		stages: stages,
+	}
+}
+
+// GetElement returns the unpacked value associated with the given key.
+// ErrNotFound is returned on a miss.
+func (p *Pipeline[K, V, MetaT]) GetElement(key K) (V, error) {
+	var zero V
+
+	data, found, err := p.cache.getElement(key)
+	if err != nil || !found {
+		return zero, notFound(found, err)
+	}
+
+	for i := len(p.stages) - 1; i >= 0; i-- {
+		if data, err = p.stages[i].Reverse(data); err != nil {
+			return zero, fmt.Errorf("cannot unpack key %v: %w", key, err)
+		}
+	}
+	return p.decode(data)
+}
+
+// CreateElement packs the value and inserts or updates its entry.
+func (p *Pipeline[K, V, MetaT]) CreateElement(key K, value V) error {
+	data, err := p.encode(value)
+	if err != nil {
+		return fmt.Errorf("cannot pack key %v: %w", key, err)
+	}
+
+	for _, stage := range p.stages {
+		if data, err = stage.Apply(data); err != nil {
+			return fmt.Errorf("cannot pack key %v: %w", key, err)
+		}
+	}
+	return p.cache.CreateElement(key, data)
+}
+
+// DeleteElement removes the entry associated with the given key.
+func (p *Pipeline[K, V, MetaT]) DeleteElement(key K) error {
+	return p.cache.DeleteElement(key)
+}
+
+// GzipStage returns a stage compressing the data with gzip.
+func GzipStage() Stage {
+	return Stage{
+		Apply: func(data []byte) ([]byte, error) {
+			var buffer bytes.Buffer
+			writer := gzip.NewWriter(&buffer)
+			if _, err := writer.Write(data); err != nil {
+				return nil, err
+			}
+			if err := writer.Close(); err != nil {
+				return nil, err
+			}
+			return buffer.Bytes(), nil
+		},
+		Reverse: func(data []byte) ([]byte, error) {
+			reader, err := gzip.NewReader(bytes.NewReader(data))
+			if err != nil {
+				return nil, err
+			}
+			defer reader.Close()
+			return io.ReadAll(reader)
+		},
+	}
+}
+
+// AESGCMStage returns a stage encrypting the data with AES-GCM.
+// The key must be 16, 24 or 32 bytes long to select AES-128, AES-192 or AES-256.
+// A random nonce is generated for every value and stored in front of it.
+func AESGCMStage(key []byte) (Stage, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return Stage{}, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return Stage{}, err
+	}
+
+	return Stage{
+		Apply: func(data []byte) ([]byte, error) {
+			nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(data)+aead.Overhead())
+			if _, err := rand.Read(nonce); err != nil {
+				return nil, err
+			}
+			return aead.Seal(nonce, nonce, data, nil), nil
+		},
+		Reverse: func(data []byte) ([]byte, error) {
+			if len(data) < aead.NonceSize() {
+				return nil, errors.New("ciphertext too short")
+			}
+			nonce, ciphertext := data[:aead.NonceSize()], data[aead.NonceSize():]
+			return aead.Open(nil, nonce, ciphertext, nil)
+		},
+	}, nil
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lru
+
+import "container/list"
+
+// InsertPosition defines where new entries are placed in the recency list.
+type InsertPosition int
+
+const (
+	// InsertAtFront places new entries as the most recently used ones. This is the default.
+	InsertAtFront InsertPosition = iota
+
+	// InsertAtMiddle places new entries at the head of the probation segment,
+	// which is the least recently used half of the list. Entries leave
+	// the probation segment when they are accessed again, so one-off scans
+	// never push the hot head out of the cache.
+	InsertAtMiddle
+
+	// InsertAtBack places new entries as the least recently used ones,
+	// making them the first eviction candidates until they are accessed.
+	InsertAtBack
+)
+
+// SetInsertPosition sets where new entries are placed in the recency list.
+// Entries already stored keep their current position.
+func (c *LRU[K, V, MetaT]) SetInsertPosition(position InsertPosition) {
+	c.lock()
+	defer c.mu.Unlock()
+
+	// Forget the current probation segment and rebuild it if needed
+	for element := c.probationHead; element != nil; element = element.Next() {
+		element.Value.(*item[K, V]).probation = false
+	}
+	c.probationHead = nil
+	c.probationLen = 0
+
+	c.insertPosition = position
+	c.balanceProbationUnsafe()
+}
+
+// pushProbationUnsafe inserts an item at the head of the probation segment without locking the LRU
+func (c *LRU[K, V, MetaT]) pushProbationUnsafe(it *item[K, V]) *list.Element {
+	var element *list.Element
+	if c.probationHead == nil {
+		element = c.list.PushBack(it)
+	} else {
+		element = c.list.InsertBefore(it, c.probationHead)
+	}
+
+	it.probation = true
+	c.probationHead = element
+	c.probationLen++
+	return element
+}
+
+// leaveProbationUnsafe takes an element out of the probation segment, if it was there,
+// without locking the LRU. It must be called before the element is moved or removed.
+func (c *LRU[K, V, MetaT]) leaveProbationUnsafe(element *list.Element) {
+	it := element.Value.(*item[K, V])
+	if !it.probation {
+		return
+	}
+
+	if c.probationHead == element {
+		c.probationHead = element.Next()
+	}
+	it.probation = false
+	c.probationLen--
+}
+
+// balanceProbationUnsafe moves the segment boundary so the probation segment
+// covers the older half of the list, without locking the LRU.
+// Every list operation changes the sizes by one at most, so this is cheap.
+func (c *LRU[K, V, MetaT]) balanceProbationUnsafe() {
+	if c.insertPosition != InsertAtMiddle {
+		return
+	}
+
+	target := c.list.Len() / 2
+
+	for c.probationLen > target {
+		c.probationHead.Value.(*item[K, V]).probation = false
+		c.probationHead = c.probationHead.Next()
+		c.probationLen--
+	}
+
+	for c.probationLen < target {
+		element := c.list.Back()
+		if c.probationHead != nil {
+			element = c.probationHead.Prev()
+		}
+		element.Value.(*item[K, V]).probation = true
+		c.probationHead = element
+		c.probationLen++
+	}
+}
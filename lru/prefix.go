@@ -0,0 +1,30 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lru
+
+import "strings"
+
+// DeleteByPrefix removes every entry whose key starts with the given prefix,
+// and returns how many entries were removed. It is useful for caches encoding
+// ownership in their keys, like "user:42:". It behaves like DeleteIf otherwise.
+// It is a function rather than a method because only caches with string keys support it,
+// e.g. lru.DeleteByPrefix(cache, "user:42:")
+func DeleteByPrefix[K ~string, V any, MetaT any](c *LRU[K, V, MetaT], prefix string) (int, error) {
+	return c.DeleteIf(func(entry Entry[K, V]) bool {
+		return strings.HasPrefix(string(entry.Key), prefix)
+	})
+}
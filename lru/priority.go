@@ -0,0 +1,84 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lru
+
+import (
+	"iter"
+	"slices"
+)
+
+// priorityLevelsUnsafe yields the priorities of the stored entries, from the lowest
+// to the highest, without locking the LRU. Entries default to priority zero,
+// and only the other levels are tracked explicitly, already sorted,
+// so nothing is allocated nor sorted on each eviction.
+func (c *LRU[K, V, MetaT]) priorityLevelsUnsafe() iter.Seq[int] {
+	return func(yield func(int) bool) {
+		zero := c.prioritized < c.list.Len()
+		for _, level := range c.levels {
+			if zero && level > 0 {
+				zero = false
+				if !yield(0) {
+					return
+				}
+			}
+			if !yield(level) {
+				return
+			}
+		}
+		if zero {
+			yield(0)
+		}
+	}
+}
+
+// setPriorityUnsafe changes the priority of an item, keeping the levels up to date,
+// without locking the LRU
+func (c *LRU[K, V, MetaT]) setPriorityUnsafe(it *item[K, V], priority int) {
+	if it.priority == priority {
+		return
+	}
+	c.forgetPriorityUnsafe(it)
+
+	it.priority = priority
+	if priority != 0 {
+		if c.priorities == nil {
+			c.priorities = make(map[int]int)
+		}
+		c.priorities[priority]++
+		c.prioritized++
+		if c.priorities[priority] == 1 {
+			position, _ := slices.BinarySearch(c.levels, priority)
+			c.levels = slices.Insert(c.levels, position, priority)
+		}
+	}
+}
+
+// forgetPriorityUnsafe stops tracking the priority of an item that is being removed
+// without locking the LRU
+func (c *LRU[K, V, MetaT]) forgetPriorityUnsafe(it *item[K, V]) {
+	if it.priority == 0 {
+		return
+	}
+	c.priorities[it.priority]--
+	c.prioritized--
+	if c.priorities[it.priority] == 0 {
+		delete(c.priorities, it.priority)
+		if position, found := slices.BinarySearch(c.levels, it.priority); found {
+			c.levels = slices.Delete(c.levels, position, position+1)
+		}
+	}
+}
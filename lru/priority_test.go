@@ -0,0 +1,48 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lru
+
+import "testing"
+
+func TestEvictionFollowsPriorities(t *testing.T) {
+	c := NewWithCapacity[string, int](3, struct{}{})
+	var candidates []string
+	c.ShouldEvict(func(_ *struct{}, _ Entry[string, int], candidate Entry[string, int]) bool {
+		candidates = append(candidates, candidate.Key)
+		return false
+	})
+
+	priorities := map[string]int{"high": 5, "default": 0, "low": -5}
+	for _, key := range []string{"default", "low", "high"} {
+		if err := c.CreateElementWithOptions(key, 1, ElementOptions{Priority: priorities[key]}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if candidates[len(candidates)-1] != "low" {
+		t.Fatalf("got candidate %q before inserting high, want low", candidates[len(candidates)-1])
+	}
+
+	for _, want := range []string{"low", "default", "high"} {
+		entry, found, err := c.RemoveOldest()
+		if err != nil || !found || entry.Key != want {
+			t.Fatalf("got (%q, %t, %v), want (%q, true, nil)", entry.Key, found, err, want)
+		}
+	}
+	if len(c.levels) != 0 || c.prioritized != 0 {
+		t.Fatalf("got levels %v and %d prioritized entries, want none", c.levels, c.prioritized)
+	}
+}
@@ -0,0 +1,49 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lru
+
+// RemovalReason tells the OnDelete handler why an entry is being removed.
+type RemovalReason int
+
+const (
+	ReasonDeleted  RemovalReason = iota // Deleted explicitly, e.g. with DeleteElement
+	ReasonEvicted                       // Evicted to make room for another entry
+	ReasonExpired                       // Removed after its TTL, when OnExpire is not defined
+	ReasonReplaced                      // Overwritten by a new value for the same key
+	ReasonPurged                        // Removed by Purge
+	ReasonCascaded                      // Deleted because an entry it depends on was removed
+)
+
+// String returns the name of the reason.
+func (r RemovalReason) String() string {
+	switch r {
+	case ReasonDeleted:
+		return "deleted"
+	case ReasonEvicted:
+		return "evicted"
+	case ReasonExpired:
+		return "expired"
+	case ReasonReplaced:
+		return "replaced"
+	case ReasonPurged:
+		return "purged"
+	case ReasonCascaded:
+		return "cascaded"
+	default:
+		return "unknown"
+	}
+}
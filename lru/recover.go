@@ -0,0 +1,28 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lru
+
+// SetPanicRecovery makes the cache recover from the panics of the OnInsert, OnDelete,
+// OnAccess, OnExpire, OnSoftExpire and OnCascade handlers, instead of letting them unwind
+// through the cache operation. Each panic is converted into a HandlerError wrapping
+// ErrHandlerPanicked, which is passed to onPanic and then handled like an error returned
+// by the handler. Asynchronous handlers are covered too. A nil onPanic disables it.
+func (c *LRU[K, V, MetaT]) SetPanicRecovery(onPanic func(err error)) {
+	c.lock()
+	defer c.mu.Unlock()
+	c.onHandlerPanic = onPanic
+}
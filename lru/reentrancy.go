@@ -0,0 +1,39 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lru
+
+// visitUnsafe calls an iteration callback with an entry, flagging the cache as iterating
+// meanwhile, so the operations called from the callback fail instead of deadlocking
+func (c *LRU[K, V, MetaT]) visitUnsafe(fn func(entry Entry[K, V]) bool, entry Entry[K, V]) bool {
+	c.iterating.Add(1)
+	defer c.iterating.Add(-1)
+	return fn(entry)
+}
+
+// lockChecked acquires the write lock of the LRU like lock, unless an iteration callback is running.
+// Goroutines cannot be told apart, so the lock is only taken if it is free then, failing with
+// ErrReentrantMutation otherwise: waiting for it would deadlock when called from the callback.
+func (c *LRU[K, V, MetaT]) lockChecked() error {
+	if c.iterating.Load() == 0 {
+		c.lock()
+		return nil
+	}
+	if !c.tryLock() {
+		return ErrReentrantMutation
+	}
+	return nil
+}
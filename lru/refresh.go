@@ -0,0 +1,133 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lru
+
+import (
+	"context"
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+// SetRefreshAhead enables proactive reloading of hot entries. When an entry is accessed
+// and less than `ratio` of its ttl remains (e.g. 0.2 for the last 20%), the loader is called
+// in the background and its result replaces the entry, keeping the same ttl.
+// Callers keep getting the current value meanwhile, so hot keys never actually expire.
+// Failed reloads are ignored, and the entry expires as usual. Entries removed or
+// overwritten while they are reloaded are left as they are.
+// Entries inserted with a SoftTTL are also reloaded once it is over, whatever the ratio.
+// A nil loader disables it, a ratio of zero only keeps reloads past the soft TTL.
+func (c *LRU[K, V, MetaT]) SetRefreshAhead(ratio float64, loader func(key K) (V, error)) {
+	c.lock()
+	defer c.mu.Unlock()
+
+	c.refreshRatio = ratio
+	c.refreshLoader = loader
+}
+
+// refreshAheadUnsafe starts a background reload of an item close to its expiration,
+// or past its soft TTL, unless a load for the same key is already running, without locking the LRU
+func (c *LRU[K, V, MetaT]) refreshAheadUnsafe(it *item[K, V], now time.Time) error {
+	if c.refreshLoader == nil {
+		return nil
+	}
+
+	softExpired := !it.softExpiresAt.IsZero() && !now.Before(it.softExpiresAt)
+	if !softExpired && !c.closeToExpirationUnsafe(it, now) {
+		return nil
+	}
+
+	key := it.entry.Key
+	if _, running := c.calls[key]; running {
+		return nil
+	}
+
+	if softExpired {
+		c.counters.softExpirations.Add(1)
+		if c.onSoftExpireHandler != nil {
+			err := runHandler("OnSoftExpire", key, c.handlerGuardUnsafe(), func() error {
+				return c.onSoftExpireHandler(&c.Metadata, c.handlerEntryUnsafe(it.entry))
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if c.calls == nil {
+		c.calls = make(map[K]*call[V])
+	}
+	current := &call[V]{
+		done:     make(chan struct{}),
+		options:  &ElementOptions{TTL: it.ttl, Grace: it.grace, SoftTTL: it.softTTL, Priority: it.priority, Cost: it.fixedCost},
+		refresh:  true,
+		element:  c.index[key],
+		storedAt: it.entry.StoredAt,
+	}
+	c.calls[key] = current
+
+	// The reload outlives the access that triggered it, so only the values of its context are kept
+	loader := c.refreshLoader
+	go c.load(context.WithoutCancel(c.contextUnsafe()), key, current, func(context.Context) (V, error) {
+		return loader(key)
+	})
+	return nil
+}
+
+// closeToExpirationUnsafe reports whether less than the refresh ratio of the ttl
+// of an item remains, without locking the LRU
+func (c *LRU[K, V, MetaT]) closeToExpirationUnsafe(it *item[K, V], now time.Time) bool {
+	if c.refreshRatio <= 0 || it.ttl <= 0 {
+		return false
+	}
+	remaining := it.ttlDeadline.Sub(now)
+	return remaining <= time.Duration(c.refreshRatio*float64(it.ttl))
+}
+
+// SetEarlyExpiration enables probabilistic early expiration (XFetch) for GetOrCreate.
+// Entries are recomputed before their deadline with a probability that grows as the
+// deadline gets closer, and with the time their loader took, so hot keys are not
+// reloaded by many callers at the same moment. A beta of 1 is a sensible default,
+// bigger values recompute earlier. Zero disables it. When an early recomputation
+// fails, the current value is returned instead of the error.
+func (c *LRU[K, V, MetaT]) SetEarlyExpiration(beta float64) {
+	c.lock()
+	defer c.mu.Unlock()
+	c.earlyExpirationBeta = max(beta, 0)
+}
+
+// expiresEarlyUnsafe decides whether an entry must be recomputed before its deadline
+// following the XFetch algorithm, without locking the LRU
+func (c *LRU[K, V, MetaT]) expiresEarlyUnsafe(key K) bool {
+	if c.earlyExpirationBeta <= 0 {
+		return false
+	}
+
+	element, found := c.index[key]
+	if !found {
+		return false
+	}
+
+	it := element.Value.(*item[K, V])
+	if it.expiresAt.IsZero() || it.loadTime <= 0 {
+		return false
+	}
+
+	// -ln(x) is positive for x in (0, 1]
+	gap := -float64(it.loadTime) * c.earlyExpirationBeta * math.Log(1-rand.Float64())
+	return !time.Now().Add(time.Duration(gap)).Before(it.expiresAt)
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lru
+
+import (
+	"testing"
+	"time"
+)
+
+// waitLoads waits for the loaders running in the background to finish
+func waitLoads[K comparable, V any, MetaT any](t *testing.T, c *LRU[K, V, MetaT]) {
+	t.Helper()
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(time.Millisecond) {
+		c.lock()
+		running := len(c.calls)
+		c.mu.Unlock()
+		if running == 0 {
+			return
+		}
+	}
+	t.Fatal("loaders still running")
+}
+
+// refreshing returns a cache whose entry "k" is being refreshed,
+// and the channel releasing the loader, which returns 99
+func refreshing(t *testing.T) (*LRU[string, int, struct{}], chan struct{}) {
+	t.Helper()
+	c := New[string, int](struct{}{})
+	release := make(chan struct{})
+	c.SetRefreshAhead(1, func(string) (int, error) {
+		<-release
+		return 99, nil
+	})
+	if err := c.CreateElementWithTTL("k", 1, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.GetElement("k"); err != nil {
+		t.Fatal(err)
+	}
+	return c, release
+}
+
+func TestRefreshAheadReplacesEntry(t *testing.T) {
+	c, release := refreshing(t)
+	close(release)
+	waitLoads(t, c)
+
+	if value, err := c.GetElement("k"); err != nil || value != 99 {
+		t.Fatalf("got (%d, %v), want 99", value, err)
+	}
+}
+
+func TestRefreshAheadSkipsDeletedEntry(t *testing.T) {
+	c, release := refreshing(t)
+	if err := c.DeleteElement("k"); err != nil {
+		t.Fatal(err)
+	}
+	close(release)
+	waitLoads(t, c)
+
+	if c.Contains("k") {
+		t.Fatal("deleted entry brought back by the refresh")
+	}
+}
+
+func TestRefreshAheadSkipsOverwrittenEntry(t *testing.T) {
+	c, release := refreshing(t)
+	if err := c.CreateElement("k", 2); err != nil {
+		t.Fatal(err)
+	}
+	close(release)
+	waitLoads(t, c)
+
+	if value, _ := c.Peek("k"); value != 2 {
+		t.Fatalf("got %d, want the overwritten value 2", value)
+	}
+}
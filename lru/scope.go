@@ -0,0 +1,74 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lru
+
+// Scope is a request-scoped cache layered over an LRU.
+// Lookups are served from a local map first and read through to the parent
+// cache on miss. A Scope is not thread-safe: it is meant to be owned by
+// a single request and discarded when the request ends.
+type Scope[K comparable, V any, MetaT any] struct {
+	parent *LRU[K, V, MetaT]
+	local  map[K]V
+}
+
+// NewScope creates a new request-scoped cache on top of the LRU.
+func (c *LRU[K, V, MetaT]) NewScope() *Scope[K, V, MetaT] {
+	return &Scope[K, V, MetaT]{
+		parent: c,
+		local:  make(map[K]V),
+	}
+}
+
+// GetElement returns the value associated with the given key.
+// Values already seen by this scope are returned without touching the parent cache.
+// ErrNotFound is returned on a miss.
+func (s *Scope[K, V, MetaT]) GetElement(key K) (V, error) {
+	if value, found := s.local[key]; found {
+		return value, nil
+	}
+
+	value, found, err := s.parent.getElement(key)
+	if err != nil || !found {
+		return value, notFound(found, err)
+	}
+
+	// Only remember hits, misses must be looked up again
+	s.local[key] = value
+	return value, nil
+}
+
+// CreateElement writes the entry through to the parent cache and keeps
+// a local copy for later lookups in the same scope.
+func (s *Scope[K, V, MetaT]) CreateElement(key K, value V) error {
+	if err := s.parent.CreateElement(key, value); err != nil {
+		return err
+	}
+	s.local[key] = value
+	return nil
+}
+
+// DeleteElement removes the entry from both the scope and the parent cache.
+func (s *Scope[K, V, MetaT]) DeleteElement(key K) error {
+	delete(s.local, key)
+	return s.parent.DeleteElement(key)
+}
+
+// Discard drops every value remembered by the scope.
+// The parent cache is left untouched.
+func (s *Scope[K, V, MetaT]) Discard() {
+	clear(s.local)
+}
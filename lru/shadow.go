@@ -0,0 +1,136 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lru
+
+import "sync/atomic"
+
+// Shadow mirrors every operation of a primary cache to a shadow cache with
+// a different configuration. Values are always served from the primary one,
+// so the shadow can be evaluated with real traffic without any risk.
+type Shadow[K comparable, V any, PrimaryMetaT any, ShadowMetaT any] struct {
+	primary *LRU[K, V, PrimaryMetaT]
+	shadow  *LRU[K, V, ShadowMetaT]
+
+	primaryHits   atomic.Uint64
+	primaryMisses atomic.Uint64
+	shadowHits    atomic.Uint64
+	shadowMisses  atomic.Uint64
+	shadowErrors  atomic.Uint64
+}
+
+// ShadowStats compares the behavior of the primary and the shadow caches.
+type ShadowStats struct {
+	PrimaryHits   uint64
+	PrimaryMisses uint64
+	ShadowHits    uint64
+	ShadowMisses  uint64
+
+	// ShadowErrors is the number of operations that failed only in the shadow cache
+	ShadowErrors uint64
+}
+
+// PrimaryHitRatio returns the ratio of lookups served by the primary cache
+func (s ShadowStats) PrimaryHitRatio() float64 {
+	return hitRatio(s.PrimaryHits, s.PrimaryMisses)
+}
+
+// ShadowHitRatio returns the ratio of lookups the shadow cache would have served
+func (s ShadowStats) ShadowHitRatio() float64 {
+	return hitRatio(s.ShadowHits, s.ShadowMisses)
+}
+
+// hitRatio returns hits/(hits+misses), or zero when there were no lookups
+func hitRatio(hits, misses uint64) float64 {
+	if hits+misses == 0 {
+		return 0
+	}
+	return float64(hits) / float64(hits+misses)
+}
+
+// NewShadow creates a Shadow that serves from primary and mirrors every operation to shadow.
+func NewShadow[K comparable, V any, PrimaryMetaT any, ShadowMetaT any](
+	primary *LRU[K, V, PrimaryMetaT], shadow *LRU[K, V, ShadowMetaT]) *Shadow[K, V, PrimaryMetaT, ShadowMetaT] {
+	return &Shadow[K, V, PrimaryMetaT, ShadowMetaT]{
+		primary: primary,
+		shadow:  shadow,
+	}
+}
+
+// CreateElement inserts or updates an entry in both caches.
+// Errors from the shadow cache are counted but never returned.
+func (s *Shadow[K, V, PrimaryMetaT, ShadowMetaT]) CreateElement(key K, value V) error {
+	if err := s.primary.CreateElement(key, value); err != nil {
+		return err
+	}
+
+	if err := s.shadow.CreateElement(key, value); err != nil {
+		s.shadowErrors.Add(1)
+	}
+	return nil
+}
+
+// GetElement returns the value from the primary cache, and looks the key up
+// in the shadow cache to compare both hit ratios.
+func (s *Shadow[K, V, PrimaryMetaT, ShadowMetaT]) GetElement(key K) (V, error) {
+	value, found, err := s.primary.getElement(key)
+	if found {
+		s.primaryHits.Add(1)
+	} else {
+		s.primaryMisses.Add(1)
+	}
+
+	_, shadowFound, shadowErr := s.shadow.getElement(key)
+	if shadowFound {
+		s.shadowHits.Add(1)
+	} else {
+		s.shadowMisses.Add(1)
+	}
+	if shadowErr != nil {
+		s.shadowErrors.Add(1)
+	}
+
+	return value, notFound(found, err)
+}
+
+// DeleteElement removes an entry by key from both caches.
+// Errors from the shadow cache are counted but never returned.
+func (s *Shadow[K, V, PrimaryMetaT, ShadowMetaT]) DeleteElement(key K) error {
+	if err := s.primary.DeleteElement(key); err != nil {
+		return err
+	}
+
+	if err := s.shadow.DeleteElement(key); err != nil {
+		s.shadowErrors.Add(1)
+	}
+	return nil
+}
+
+// Primary returns the cache used to serve values.
+func (s *Shadow[K, V, PrimaryMetaT, ShadowMetaT]) Primary() *LRU[K, V, PrimaryMetaT] {
+	return s.primary
+}
+
+// Stats returns the hit and miss counters of both caches.
+func (s *Shadow[K, V, PrimaryMetaT, ShadowMetaT]) Stats() ShadowStats {
+	return ShadowStats{
+		PrimaryHits:   s.primaryHits.Load(),
+		PrimaryMisses: s.primaryMisses.Load(),
+		ShadowHits:    s.shadowHits.Load(),
+		ShadowMisses:  s.shadowMisses.Load(),
+		ShadowErrors:  s.shadowErrors.Load(),
+	}
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lru
+
+// SetCostFunc enables cost tracking. The function returns the cost of an entry,
+// usually its size in bytes, and is called every time an entry is inserted or updated.
+// Entries already stored are measured again. A nil function disables cost tracking.
+func (c *LRU[K, V, MetaT]) SetCostFunc(costFunc func(entry Entry[K, V]) int64) {
+	c.lock()
+	defer c.mu.Unlock()
+
+	c.costFunc = costFunc
+	for element := c.list.Front(); element != nil; element = element.Next() {
+		it := element.Value.(*item[K, V])
+		entry := it.entry
+		entry.Cost = c.costUnsafe(entry, it.fixedCost)
+		c.setEntryUnsafe(it, entry)
+	}
+}
+
+// Len returns the amount of entries stored in the cache.
+func (c *LRU[K, V, MetaT]) Len() int {
+	c.rlock()
+	defer c.mu.RUnlock()
+	return c.list.Len()
+}
+
+// SizeBytes returns the sum of the costs of all the entries stored in the cache.
+// It only accounts the entries inserted with a Cost when cost tracking is not enabled with SetCostFunc.
+func (c *LRU[K, V, MetaT]) SizeBytes() int64 {
+	c.rlock()
+	defer c.mu.RUnlock()
+	return c.size
+}
+
+// setEntryUnsafe stores an entry into an item, keeping the total cost and the indexes up to date,
+// without locking the LRU
+func (c *LRU[K, V, MetaT]) setEntryUnsafe(it *item[K, V], entry Entry[K, V]) {
+	c.size += entry.Cost - it.entry.Cost
+	it.entry = entry
+	c.indexUnsafe(it)
+}
+
+// costUnsafe returns the cost of an entry: the fixed one given with its options,
+// or the one returned by the cost function, without locking the LRU
+func (c *LRU[K, V, MetaT]) costUnsafe(entry Entry[K, V], fixedCost int64) int64 {
+	switch {
+	case fixedCost > 0:
+		return fixedCost
+	case c.costFunc != nil:
+		return c.costFunc(entry)
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,173 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lru
+
+import "container/list"
+
+// SmallThreshold is the capacity up to which Small keeps its entries in a single slice.
+// Above it, shifting the slice on every access costs more than a map and a list,
+// as measured by the benchmarks of small_test.go.
+const SmallThreshold = 16
+
+// Small is a minimal LRU cache for a handful of entries, like the tiny caches
+// kept per connection. Up to SmallThreshold entries, they live in a single slice ordered
+// from the most to the least recently used, so lookups are linear scans and no map nor
+// list elements are allocated. Larger capacities switch automatically to a map and a list.
+// Taking no lock, it is several times faster than LRU at any size.
+// Small has none of the features of LRU (handlers, TTLs...) and, like Scope,
+// it is not thread-safe: it is meant to be owned by a single goroutine.
+type Small[K comparable, V any] struct {
+	entries  []Entry[K, V]
+	capacity int
+
+	// Used instead of entries above SmallThreshold
+	index map[K]*list.Element
+	list  *list.List
+}
+
+// NewSmall creates a new Small cache that keeps at most `capacity` entries,
+// evicting the least recently used ones automatically when full.
+// Negative capacities are treated as zero, so nothing is kept.
+func NewSmall[K comparable, V any](capacity int) *Small[K, V] {
+	capacity = max(capacity, 0)
+	if capacity > SmallThreshold {
+		return &Small[K, V]{
+			capacity: capacity,
+			index:    make(map[K]*list.Element, capacity),
+			list:     list.New(),
+		}
+	}
+	return &Small[K, V]{
+		entries:  make([]Entry[K, V], 0, capacity),
+		capacity: capacity,
+	}
+}
+
+// Get returns the value associated with the given key and whether it was found,
+// marking the entry as the most recently used.
+func (s *Small[K, V]) Get(key K) (V, bool) {
+	if s.list != nil {
+		element, found := s.index[key]
+		if !found {
+			var zero V
+			return zero, false
+		}
+		s.list.MoveToFront(element)
+		return element.Value.(*Entry[K, V]).Value, true
+	}
+
+	i := s.find(key)
+	if i < 0 {
+		var zero V
+		return zero, false
+	}
+	s.promote(i)
+	return s.entries[0].Value, true
+}
+
+// Set creates or updates the entry associated with the given key, marking it
+// as the most recently used. The least recently used entry is evicted when full.
+func (s *Small[K, V]) Set(key K, value V) {
+	if s.list != nil {
+		s.setElement(key, value)
+		return
+	}
+
+	i := s.find(key)
+	switch {
+	case i >= 0:
+	case len(s.entries) < s.capacity:
+		s.entries = append(s.entries, Entry[K, V]{})
+		i = len(s.entries) - 1
+	case s.capacity > 0:
+		i = len(s.entries) - 1
+	default:
+		return
+	}
+
+	s.promote(i)
+	s.entries[0] = Entry[K, V]{Key: key, Value: value}
+}
+
+// Delete removes the entry associated with the given key, and reports whether it was found.
+func (s *Small[K, V]) Delete(key K) bool {
+	if s.list != nil {
+		element, found := s.index[key]
+		if !found {
+			return false
+		}
+		delete(s.index, key)
+		s.list.Remove(element)
+		return true
+	}
+
+	i := s.find(key)
+	if i < 0 {
+		return false
+	}
+	last := len(s.entries) - 1
+	copy(s.entries[i:], s.entries[i+1:])
+	s.entries[last] = Entry[K, V]{} // Drop the references held by the freed slot
+	s.entries = s.entries[:last]
+	return true
+}
+
+// Len returns the amount of entries in the cache.
+func (s *Small[K, V]) Len() int {
+	if s.list != nil {
+		return s.list.Len()
+	}
+	return len(s.entries)
+}
+
+// find returns the position of the given key, or -1 when it is missing
+func (s *Small[K, V]) find(key K) int {
+	for i := range s.entries {
+		if s.entries[i].Key == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// promote moves the entry at position i to the front, shifting the ones before it
+func (s *Small[K, V]) promote(i int) {
+	entry := s.entries[i]
+	copy(s.entries[1:i+1], s.entries[:i])
+	s.entries[0] = entry
+}
+
+// setElement creates or updates an entry of the map and list storage,
+// reusing the element of the least recently used entry when full
+func (s *Small[K, V]) setElement(key K, value V) {
+	if element, found := s.index[key]; found {
+		element.Value.(*Entry[K, V]).Value = value
+		s.list.MoveToFront(element)
+		return
+	}
+
+	if s.list.Len() < s.capacity {
+		s.index[key] = s.list.PushFront(&Entry[K, V]{Key: key, Value: value})
+		return
+	}
+	element := s.list.Back()
+	entry := element.Value.(*Entry[K, V])
+	delete(s.index, entry.Key)
+	*entry = Entry[K, V]{Key: key, Value: value}
+	s.index[key] = element
+	s.list.MoveToFront(element)
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lru
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"testing"
+)
+
+func TestSmall(t *testing.T) {
+	for _, capacity := range []int{3, SmallThreshold + 1} {
+		t.Run(fmt.Sprint(capacity), func(t *testing.T) {
+			s := NewSmall[int, int](capacity)
+			for i := range capacity {
+				s.Set(i, i)
+			}
+			if _, found := s.Get(0); !found {
+				t.Fatal("key 0 not found")
+			}
+
+			// Key 1 is now the least recently used one
+			s.Set(capacity, capacity)
+			if _, found := s.Get(1); found {
+				t.Fatal("key 1 was not evicted")
+			}
+			if s.Len() != capacity {
+				t.Fatalf("got %d entries, want %d", s.Len(), capacity)
+			}
+
+			s.Set(0, 42)
+			if value, _ := s.Get(0); value != 42 {
+				t.Fatalf("got %d, want 42", value)
+			}
+			if !s.Delete(0) || s.Delete(0) {
+				t.Fatal("key 0 was not deleted once")
+			}
+			if s.Len() != capacity-1 {
+				t.Fatalf("got %d entries, want %d", s.Len(), capacity-1)
+			}
+		})
+	}
+}
+
+func TestSmallNegativeCapacity(t *testing.T) {
+	s := NewSmall[int, int](-1)
+	s.Set(1, 1)
+	if s.Len() != 0 {
+		t.Fatalf("got %d entries, want 0", s.Len())
+	}
+}
+
+// benchmarkSizes are the amounts of entries compared, around SmallThreshold
+var benchmarkSizes = []int{8, 16, 32, 64, 128}
+
+// benchmarkKeys returns a fixed pseudo-random sequence of keys below n,
+// as accessing them in order always hits the least recently used entry
+func benchmarkKeys(n int) []int {
+	r := rand.New(rand.NewPCG(1, 2))
+	keys := make([]int, 1024)
+	for i := range keys {
+		keys[i] = r.IntN(n)
+	}
+	return keys
+}
+
+func BenchmarkSmallGet(b *testing.B) {
+	for _, size := range benchmarkSizes {
+		b.Run(fmt.Sprint(size), func(b *testing.B) {
+			s := NewSmall[int, int](size)
+			for i := range size {
+				s.Set(i, i)
+			}
+			keys := benchmarkKeys(size)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := range b.N {
+				s.Get(keys[i%len(keys)])
+			}
+		})
+	}
+}
+
+func BenchmarkLRUGet(b *testing.B) {
+	for _, size := range benchmarkSizes {
+		b.Run(fmt.Sprint(size), func(b *testing.B) {
+			c := NewWithCapacity[int, int](size, struct{}{})
+			for i := range size {
+				c.CreateElement(i, i)
+			}
+			keys := benchmarkKeys(size)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := range b.N {
+				c.GetElement(keys[i%len(keys)])
+			}
+		})
+	}
+}
+
+func BenchmarkSmallSet(b *testing.B) {
+	for _, size := range benchmarkSizes {
+		b.Run(fmt.Sprint(size), func(b *testing.B) {
+			s := NewSmall[int, int](size)
+			keys := benchmarkKeys(2 * size)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := range b.N {
+				s.Set(keys[i%len(keys)], i)
+			}
+		})
+	}
+}
+
+func BenchmarkLRUSet(b *testing.B) {
+	for _, size := range benchmarkSizes {
+		b.Run(fmt.Sprint(size), func(b *testing.B) {
+			c := NewWithCapacity[int, int](size, struct{}{})
+			keys := benchmarkKeys(2 * size)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := range b.N {
+				c.CreateElement(keys[i%len(keys)], i)
+			}
+		})
+	}
+}
@@ -0,0 +1,213 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lru
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// lockWaitSampleRate defines how many contended lock acquisitions happen
+// for each one whose wait time is measured
+const lockWaitSampleRate = 8
+
+// lockWaitBounds are the upper bounds of the lock wait-time histogram buckets.
+// An extra bucket catches everything above the last bound.
+var lockWaitBounds = [...]time.Duration{
+	time.Microsecond,
+	10 * time.Microsecond,
+	100 * time.Microsecond,
+	time.Millisecond,
+	10 * time.Millisecond,
+	100 * time.Millisecond,
+	time.Second,
+}
+
+// Stats is a point-in-time snapshot of the cache statistics.
+type Stats struct {
+	// LockAcquisitions is the number of times the cache lock was acquired
+	LockAcquisitions uint64
+
+	// LockContentions is the number of acquisitions that had to wait for another holder
+	LockContentions uint64
+
+	// LockWait is a sampled histogram of the time spent waiting for the lock
+	LockWait LockWaitStats
+
+	// Hits and Misses are the number of lookups that found, or not, the key
+	Hits   uint64
+	Misses uint64
+
+	// Evictions is the number of entries removed to make room for new ones
+	Evictions uint64
+
+	// Expirations is the number of entries removed after their TTL
+	Expirations uint64
+
+	// SoftExpirations is the number of background reloads started
+	// by accesses to entries past their soft TTL
+	SoftExpirations uint64
+
+	// Pinned is the number of entries currently protected from eviction with Pin
+	Pinned uint64
+
+	// DroppedHandlerCalls is the number of asynchronous handler calls discarded
+	// because the queue of the pool was full
+	DroppedHandlerCalls uint64
+
+	// HandlerTimeouts is the number of handler calls abandoned after the timeout
+	// set with SetHandlerTimeout
+	HandlerTimeouts uint64
+
+	// GhostHits is the number of misses on keys that had been recently evicted.
+	// A high value means the cache would benefit from a bigger capacity.
+	GhostHits uint64
+
+	// Bypassed is the number of GetOrCreate calls served straight by the loader
+	// while the bypass mode set with SetBypass was enabled
+	Bypassed uint64
+}
+
+// LockWaitStats is a histogram of the time spent waiting for the cache lock.
+// Only one out of every few contended acquisitions is measured.
+type LockWaitStats struct {
+	Samples uint64
+	Total   time.Duration
+	Buckets []LockWaitBucket
+}
+
+// LockWaitBucket counts the sampled waits lower or equal than UpperBound.
+// The last bucket has a zero UpperBound and counts everything above the previous one.
+type LockWaitBucket struct {
+	UpperBound time.Duration
+	Count      uint64
+}
+
+// counters holds the cache operation counters
+type counters struct {
+	hits                atomic.Uint64
+	misses              atomic.Uint64
+	evictions           atomic.Uint64
+	expirations         atomic.Uint64
+	softExpirations     atomic.Uint64
+	pinned              atomic.Int64 // Gauge rather than counter
+	droppedHandlerCalls atomic.Uint64
+	handlerTimeouts     atomic.Uint64
+	ghostHits           atomic.Uint64
+	bypassed            atomic.Uint64
+}
+
+// lockStats holds the lock counters. It is updated with atomics as
+// read locks can be held concurrently.
+type lockStats struct {
+	acquisitions atomic.Uint64
+	contentions  atomic.Uint64
+	samples      atomic.Uint64
+	totalWait    atomic.Int64
+	buckets      [len(lockWaitBounds) + 1]atomic.Uint64
+}
+
+// observe records a sampled wait time into the histogram
+func (s *lockStats) observe(wait time.Duration) {
+	s.samples.Add(1)
+	s.totalWait.Add(int64(wait))
+
+	bucket := len(lockWaitBounds)
+	for i, bound := range lockWaitBounds {
+		if wait <= bound {
+			bucket = i
+			break
+		}
+	}
+	s.buckets[bucket].Add(1)
+}
+
+// lock acquires the write lock of the LRU recording contention statistics
+func (c *LRU[K, V, MetaT]) lock() {
+	c.acquire(c.mu.TryLock, c.mu.Lock)
+}
+
+// rlock acquires the read lock of the LRU recording contention statistics
+func (c *LRU[K, V, MetaT]) rlock() {
+	c.acquire(c.mu.TryRLock, c.mu.RLock)
+}
+
+// tryLock acquires the write lock of the LRU only if it is free,
+// recording contention statistics
+func (c *LRU[K, V, MetaT]) tryLock() bool {
+	if !c.mu.TryLock() {
+		c.lockStats.contentions.Add(1)
+		return false
+	}
+	c.lockStats.acquisitions.Add(1)
+	c.injectLockDelay()
+	return true
+}
+
+// acquire tries the fast path first and only measures the slow one
+func (c *LRU[K, V, MetaT]) acquire(tryLock func() bool, lock func()) {
+	if tryLock() {
+		c.lockStats.acquisitions.Add(1)
+		c.injectLockDelay()
+		return
+	}
+
+	sampled := c.lockStats.contentions.Add(1)%lockWaitSampleRate == 0
+	var start time.Time
+	if sampled {
+		start = time.Now()
+	}
+
+	lock()
+	c.lockStats.acquisitions.Add(1)
+	c.injectLockDelay()
+
+	if sampled {
+		c.lockStats.observe(time.Since(start))
+	}
+}
+
+// Stats returns a snapshot of the cache statistics.
+func (c *LRU[K, V, MetaT]) Stats() Stats {
+	stats := Stats{
+		LockAcquisitions:    c.lockStats.acquisitions.Load(),
+		LockContentions:     c.lockStats.contentions.Load(),
+		Hits:                c.counters.hits.Load(),
+		Misses:              c.counters.misses.Load(),
+		Evictions:           c.counters.evictions.Load(),
+		Expirations:         c.counters.expirations.Load(),
+		SoftExpirations:     c.counters.softExpirations.Load(),
+		Pinned:              uint64(c.counters.pinned.Load()),
+		DroppedHandlerCalls: c.counters.droppedHandlerCalls.Load(),
+		HandlerTimeouts:     c.counters.handlerTimeouts.Load(),
+		GhostHits:           c.counters.ghostHits.Load(),
+		Bypassed:            c.counters.bypassed.Load(),
+		LockWait: LockWaitStats{
+			Samples: c.lockStats.samples.Load(),
+			Total:   time.Duration(c.lockStats.totalWait.Load()),
+			Buckets: make([]LockWaitBucket, len(c.lockStats.buckets)),
+		},
+	}
+
+	for i := range c.lockStats.buckets {
+		if i < len(lockWaitBounds) {
+			stats.LockWait.Buckets[i].UpperBound = lockWaitBounds[i]
+		}
+		stats.LockWait.Buckets[i].Count = c.lockStats.buckets[i].Load()
+	}
+	return stats
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lru
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrHandlerTimeout is wrapped by the HandlerError reporting a handler call
+// abandoned after the timeout set with SetHandlerTimeout.
+var ErrHandlerTimeout = errors.New("handler timed out")
+
+// SetHandlerTimeout bounds the time the cache waits for each call of the OnInsert, OnDelete,
+// OnAccess, OnExpire, OnSoftExpire and OnCascade handlers, so a stuck handler cannot wedge
+// the operation, and the eviction, holding the cache lock. Once the timeout expires, the call
+// is abandoned and the operation goes on as if the handler had succeeded: the overrun is
+// counted in Stats, and reported to onOverrun, if not nil, as a HandlerError wrapping
+// ErrHandlerTimeout. Abandoned handlers keep running in their own goroutine, outside the lock,
+// so any access to the metadata must be synchronized. As handlers no longer run in the goroutine
+// of the operation, their panics crash the program unless SetPanicRecovery is used.
+// Zero disables the timeout.
+func (c *LRU[K, V, MetaT]) SetHandlerTimeout(timeout time.Duration, onOverrun func(err error)) {
+	c.lock()
+	defer c.mu.Unlock()
+	c.handlerTimeout = max(timeout, 0)
+	c.onHandlerOverrun = onOverrun
+}
+
+// runHandlerWithTimeout calls a handler in its own goroutine, waiting for it
+// at most the timeout of the guard
+func runHandlerWithTimeout[K comparable](hook string, key K, guard handlerGuard, call func() error) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- callHandler(hook, key, guard, call)
+	}()
+
+	timer := time.NewTimer(guard.timeout)
+	defer timer.Stop()
+
+	select {
+	case err := <-done:
+		return err
+	case <-timer.C:
+		guard.overruns.Add(1)
+		if guard.onOverrun != nil {
+			guard.onOverrun(&HandlerError{Hook: hook, Key: key, Err: fmt.Errorf("%w after %s", ErrHandlerTimeout, guard.timeout)})
+		}
+		return nil
+	}
+}
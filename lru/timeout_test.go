@@ -0,0 +1,53 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lru
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestHandlerTimeoutAbandonsStuckHandler(t *testing.T) {
+	c := New[string, int](struct{}{})
+	release := make(chan struct{})
+	defer close(release)
+	c.OnInsert(func(*struct{}, Entry[string, int]) error {
+		<-release
+		return errors.New("too late to matter")
+	})
+
+	var overrun error
+	c.SetHandlerTimeout(5*time.Millisecond, func(err error) { overrun = err })
+
+	if err := c.CreateElement("a", 1); err != nil {
+		t.Fatalf("got error %v, want the handler abandoned", err)
+	}
+	if !errors.Is(overrun, ErrHandlerTimeout) {
+		t.Fatalf("got overrun %v, want ErrHandlerTimeout", overrun)
+	}
+	var handlerErr *HandlerError
+	if !errors.As(overrun, &handlerErr) || handlerErr.Hook != "OnInsert" {
+		t.Fatalf("got overrun %v, want an OnInsert HandlerError", overrun)
+	}
+	if got := c.Stats().HandlerTimeouts; got != 1 {
+		t.Fatalf("got %d timeouts, want 1", got)
+	}
+	if !c.Contains("a") {
+		t.Fatal("got a missing, want it inserted")
+	}
+}
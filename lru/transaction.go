@@ -0,0 +1,50 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lru
+
+import (
+	"container/list"
+	"errors"
+)
+
+// SetTransactionalInsert makes the insertions whose OnInsert handler fails remove the
+// element they added, without running any other handler, so the cache stays consistent
+// with the metadata maintained by the handlers (e.g. disk accounting). The handler error
+// is returned. Entries depending on an updated key are still removed with it, as usual.
+// The replaced value of an update is not restored, as its OnDelete handler
+// already ran, nor are the entries evicted to make room. Asynchronous OnInsert handlers
+// cannot fail the insertion, so they are not affected.
+func (c *LRU[K, V, MetaT]) SetTransactionalInsert(enabled bool) {
+	c.lock()
+	defer c.mu.Unlock()
+	c.transactionalInsert = enabled
+}
+
+// rollbackInsertUnsafe removes the element added by a failed insertion
+// without running its handlers nor locking the LRU. New entries were never journaled,
+// so only the removal of an updated one is.
+func (c *LRU[K, V, MetaT]) rollbackInsertUnsafe(element *list.Element, updated bool) error {
+	key := element.Value.(*item[K, V]).entry.Key
+	delete(c.index, key)
+	c.removeUnsafe(element)
+
+	var err error
+	if updated {
+		err = c.journalRemovalUnsafe(key, ReasonDeleted)
+	}
+	return errors.Join(err, c.cascadeUnsafe(key))
+}
@@ -0,0 +1,391 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lru
+
+import (
+	"container/heap"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+)
+
+// CreateElementWithTTL inserts or updates an entry that expires after ttl.
+// Expired entries are treated as missing, and are removed, running the OnExpire
+// handler, the next time they are accessed. A zero ttl means the entry never expires.
+func (c *LRU[K, V, MetaT]) CreateElementWithTTL(key K, value V, ttl time.Duration) error {
+	if err := c.lockChecked(); err != nil {
+		return err
+	}
+	defer c.mu.Unlock()
+	return c.createElementUnsafe(key, value, &ElementOptions{TTL: max(ttl, 0)})
+}
+
+// CreateElementWithOptions inserts or updates an entry using the given per-entry settings.
+func (c *LRU[K, V, MetaT]) CreateElementWithOptions(key K, value V, options ElementOptions) error {
+	if err := c.lockChecked(); err != nil {
+		return err
+	}
+	defer c.mu.Unlock()
+
+	options.TTL = max(options.TTL, 0)
+	return c.createElementUnsafe(key, value, &options)
+}
+
+// applyOptionsUnsafe sets the deadlines of an item from its options, without locking the LRU.
+// Nil options use the TTL function or the default ttl.
+func (c *LRU[K, V, MetaT]) applyOptionsUnsafe(it *item[K, V], options *ElementOptions) {
+	ttl, grace, priority := time.Duration(0), time.Duration(0), 0
+	if options == nil {
+		ttl = c.entryTTLUnsafe(it.entry)
+	} else {
+		ttl, grace, priority = options.TTL, options.Grace, options.Priority
+	}
+	c.setPriorityUnsafe(it, priority)
+
+	if grace <= 0 {
+		grace = c.gracePeriod
+	}
+	it.grace = grace
+	it.softTTL, it.softExpiresAt = 0, time.Time{}
+	if options != nil && options.SoftTTL > 0 {
+		it.softTTL = options.SoftTTL
+		it.softExpiresAt = time.Now().Add(options.SoftTTL)
+	}
+	it.ttl = ttl
+	it.ttlDeadline = c.deadlineUnsafe(ttl)
+	c.refreshDeadlineUnsafe(it)
+}
+
+// Touch sets a new ttl for the entry associated with the given key without rewriting its value,
+// and reports whether it was found. A zero ttl makes the entry never expire.
+// When promote is true, the entry is also moved to the front (most recently used).
+// Expired entries cannot be touched. Frozen or closed caches reject it.
+func (c *LRU[K, V, MetaT]) Touch(key K, ttl time.Duration, promote bool) (bool, error) {
+	if err := c.lockChecked(); err != nil {
+		return false, err
+	}
+	defer c.mu.Unlock()
+
+	if err := c.writableUnsafe(); err != nil {
+		return false, err
+	}
+
+	element, found := c.index[key]
+	if !found {
+		return false, nil
+	}
+
+	it := element.Value.(*item[K, V])
+	if it.expired(time.Now()) {
+		return false, nil
+	}
+
+	it.ttl = ttl
+	it.ttlDeadline = c.deadlineUnsafe(ttl)
+	c.refreshDeadlineUnsafe(it)
+	if promote {
+		c.promoteUnsafe(element)
+	}
+	return true, nil
+}
+
+// TTL returns the remaining lifetime of the entry associated with the given key,
+// and whether it was found, without updating its recency.
+// A zero duration is returned for the entries that never expire.
+func (c *LRU[K, V, MetaT]) TTL(key K) (time.Duration, bool) {
+	c.rlock()
+	defer c.mu.RUnlock()
+
+	element, found := c.index[key]
+	if !found {
+		return 0, false
+	}
+
+	it := element.Value.(*item[K, V])
+	now := time.Now()
+	if it.expired(now) {
+		return 0, false
+	}
+	if it.expiresAt.IsZero() {
+		return 0, true
+	}
+	return it.expiresAt.Sub(now), true
+}
+
+// GetWithExpiry behaves like GetElement, but also returns the moment the entry expires.
+// The zero time is returned for the entries that never expire, and for the missing ones.
+func (c *LRU[K, V, MetaT]) GetWithExpiry(key K) (V, time.Time, error) {
+	if err := c.lockChecked(); err != nil {
+		var zero V
+		return zero, time.Time{}, err
+	}
+	defer c.mu.Unlock()
+
+	value, found, err := c.getElementUnsafe(key)
+	if !found {
+		return value, time.Time{}, notFound(found, err)
+	}
+	return value, c.index[key].Value.(*item[K, V]).expiresAt, err
+}
+
+// SetDefaultTTL sets the ttl applied to the entries inserted without an explicit one,
+// like the ones created by CreateElement or GetOrCreate, when no TTL function
+// is set or it returns no ttl. Zero disables it.
+// Entries already stored keep their current deadline.
+func (c *LRU[K, V, MetaT]) SetDefaultTTL(ttl time.Duration) {
+	c.lock()
+	defer c.mu.Unlock()
+	c.defaultTTL = ttl
+}
+
+// SetIdleTimeout makes entries expire when they are not accessed within the given window.
+// The deadline slides on every access, and is combined with the ttl, so entries expire
+// on whichever comes first. Peek and Contains do not count as accesses.
+// Zero disables it. Entries already stored start counting their idle time now.
+func (c *LRU[K, V, MetaT]) SetIdleTimeout(timeout time.Duration) {
+	c.lock()
+	defer c.mu.Unlock()
+
+	c.idleTimeout = max(timeout, 0)
+	for element := c.list.Front(); element != nil; element = element.Next() {
+		c.refreshDeadlineUnsafe(element.Value.(*item[K, V]))
+	}
+}
+
+// refreshDeadlineUnsafe recomputes when an item expires from its ttl and the idle timeout,
+// without locking the LRU
+func (c *LRU[K, V, MetaT]) refreshDeadlineUnsafe(it *item[K, V]) {
+	expiresAt := it.ttlDeadline
+	if c.idleTimeout > 0 {
+		idleDeadline := time.Now().Add(c.idleTimeout)
+		if expiresAt.IsZero() || idleDeadline.Before(expiresAt) {
+			expiresAt = idleDeadline
+		}
+	}
+	if it.pinned && c.pinnedNeverExpire {
+		expiresAt = time.Time{}
+	}
+	c.setDeadlineUnsafe(it, expiresAt)
+}
+
+// SetTTLFunc sets a function that computes the ttl of the entries inserted without
+// an explicit one, so expiration can be derived from the value itself
+// (e.g. a max-age embedded in an API response). When the function returns
+// zero or a negative duration, the default ttl is used instead.
+// A nil function disables it.
+func (c *LRU[K, V, MetaT]) SetTTLFunc(ttlFunc func(entry Entry[K, V]) time.Duration) {
+	c.lock()
+	defer c.mu.Unlock()
+	c.ttlFunc = ttlFunc
+}
+
+// entryTTLUnsafe returns the ttl of an entry inserted without an explicit one,
+// without locking the LRU
+func (c *LRU[K, V, MetaT]) entryTTLUnsafe(entry Entry[K, V]) time.Duration {
+	if c.ttlFunc != nil {
+		if ttl := c.ttlFunc(entry); ttl > 0 {
+			return ttl
+		}
+	}
+	return c.defaultTTL
+}
+
+// DeleteExpired removes every expired entry whose grace period is over,
+// running the OnExpire handler for each of them,
+// and returns how many were removed. Entries whose handler fails are kept,
+// and the errors are returned joined.
+func (c *LRU[K, V, MetaT]) DeleteExpired() (int, error) {
+	if err := c.lockChecked(); err != nil {
+		return 0, err
+	}
+	defer c.mu.Unlock()
+	return c.deleteExpiredUnsafe()
+}
+
+// deleteExpiredUnsafe removes every expired entry without locking the LRU.
+// Items are taken from the expirations heap, so only the expired ones are visited.
+func (c *LRU[K, V, MetaT]) deleteExpiredUnsafe() (int, error) {
+	var errs []error
+	var failed []*item[K, V]
+	now := time.Now()
+	deleted := 0
+
+	for len(c.expirations) > 0 && c.expirations[0].removable(now) {
+		it := c.expirations[0]
+		removed, err := c.expireElementUnsafe(it.entry.Key)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		if removed {
+			deleted++
+			continue
+		}
+
+		// Keep the item aside so the next one can be reached
+		heap.Remove(&c.expirations, it.heapIndex)
+		failed = append(failed, it)
+	}
+
+	for _, it := range failed {
+		heap.Push(&c.expirations, it)
+	}
+	return deleted, errors.Join(errs...)
+}
+
+// StartJanitor starts a background goroutine that removes the expired entries
+// every interval. A janitor already running is stopped first.
+// Errors returned by the handlers are ignored, the affected entries are retried on the next run.
+// An error is returned, and nothing is started nor stopped, when interval is not positive.
+func (c *LRU[K, V, MetaT]) StartJanitor(interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("janitor interval must be positive, got %s", interval)
+	}
+	c.StopJanitor()
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+
+	c.lock()
+	c.janitorStop = stop
+	c.janitorDone = done
+	c.mu.Unlock()
+
+	go func() {
+		defer close(done)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				_, _ = c.DeleteExpired()
+			}
+		}
+	}()
+	return nil
+}
+
+// StopJanitor stops the background janitor, if running, and waits for it to exit.
+func (c *LRU[K, V, MetaT]) StopJanitor() {
+	c.lock()
+	stop, done := c.janitorStop, c.janitorDone
+	c.janitorStop, c.janitorDone = nil, nil
+	c.mu.Unlock()
+
+	if stop == nil {
+		return
+	}
+	close(stop)
+	<-done
+}
+
+// deadlineUnsafe returns the moment an entry created now with the given ttl expires,
+// or the zero time when it never expires, without locking the LRU.
+// The configured jitter is applied to the ttl.
+func (c *LRU[K, V, MetaT]) deadlineUnsafe(ttl time.Duration) time.Time {
+	if ttl <= 0 {
+		return time.Time{}
+	}
+
+	if c.ttlJitter > 0 {
+		// Spread the ttl uniformly within ±jitter
+		factor := 1 + c.ttlJitter*(2*rand.Float64()-1)
+		ttl = max(time.Duration(float64(ttl)*factor), 1)
+	}
+	return time.Now().Add(ttl)
+}
+
+// SetTTLJitter sets a fraction, between 0 and 1, used to randomly spread every ttl.
+// For example, 0.1 turns a ttl of 10 minutes into one between 9 and 11 minutes,
+// so entries inserted in a burst do not expire all at the same time.
+// Zero disables it.
+func (c *LRU[K, V, MetaT]) SetTTLJitter(fraction float64) {
+	c.lock()
+	defer c.mu.Unlock()
+	c.ttlJitter = min(max(fraction, 0), 1)
+}
+
+// expired reports whether the item is expired at the given moment
+func (it *item[K, V]) expired(now time.Time) bool {
+	return !it.expiresAt.IsZero() && !now.Before(it.expiresAt)
+}
+
+// removable reports whether the item is expired and out of its grace period at the given moment
+func (it *item[K, V]) removable(now time.Time) bool {
+	return !it.expiresAt.IsZero() && !now.Before(it.removeAt())
+}
+
+// removeAt returns the moment the item stops being servable as stale
+func (it *item[K, V]) removeAt() time.Time {
+	return it.expiresAt.Add(it.grace)
+}
+
+// SetGracePeriod sets how long expired entries are kept to be served as stale
+// by GetStale, or by GetOrCreate when the loader fails. It applies to the entries
+// inserted from now on that do not define their own grace. Zero disables it.
+func (c *LRU[K, V, MetaT]) SetGracePeriod(grace time.Duration) {
+	c.lock()
+	defer c.mu.Unlock()
+	c.gracePeriod = max(grace, 0)
+}
+
+// GetStale behaves like GetElement, but also returns the expired entries that are still
+// within their grace period. The stale result reports whether the returned value is expired.
+func (c *LRU[K, V, MetaT]) GetStale(key K) (value V, stale bool, err error) {
+	if err := c.lockChecked(); err != nil {
+		return value, false, err
+	}
+	defer c.mu.Unlock()
+
+	element, found := c.index[key]
+	if !found {
+		return value, false, ErrNotFound
+	}
+
+	now := time.Now()
+	it := element.Value.(*item[K, V])
+	if it.removable(now) {
+		_, err := c.expireElementUnsafe(key)
+		return value, false, notFound(false, err)
+	}
+
+	stale = it.expired(now)
+	value, err = c.accessElementUnsafe(element, stale)
+	return value, stale, err
+}
+
+// staleValueUnsafe returns the value of an expired entry still within its grace period
+// without locking the LRU
+func (c *LRU[K, V, MetaT]) staleValueUnsafe(key K) (V, bool) {
+	element, found := c.index[key]
+	if !found {
+		var zero V
+		return zero, false
+	}
+
+	now := time.Now()
+	it := element.Value.(*item[K, V])
+	if !it.expired(now) || it.removable(now) {
+		var zero V
+		return zero, false
+	}
+	return it.entry.Value, true
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lru
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// expiredParent returns a cache holding an expired "parent" entry, with a live
+// "child" depending on it, whose cascade always fails
+func expiredParent(t *testing.T) *LRU[string, int, struct{}] {
+	t.Helper()
+	c := New[string, int](struct{}{})
+	if err := c.CreateElementWithTTL("parent", 1, time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.CreateElement("child", 2); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.AddDependency("child", "parent"); err != nil {
+		t.Fatal(err)
+	}
+	c.OnCascade(func(*struct{}, string, Entry[string, int]) error {
+		return errors.New("cascade refused")
+	})
+	time.Sleep(5 * time.Millisecond)
+	return c
+}
+
+func TestDeleteExpiredWithFailedCascade(t *testing.T) {
+	c := expiredParent(t)
+
+	deleted, err := c.DeleteExpired()
+	if !errors.Is(err, ErrCascadeFailed) {
+		t.Fatalf("got error %v, want ErrCascadeFailed", err)
+	}
+	if deleted != 1 {
+		t.Fatalf("got %d deleted, want 1", deleted)
+	}
+	if c.Contains("parent") || !c.Contains("child") {
+		t.Fatalf("got keys %v, want only child", c.Keys())
+	}
+	if got := c.Stats().Expirations; got != 1 {
+		t.Fatalf("got %d expirations, want 1", got)
+	}
+}
+
+func TestExpireWithFailedCascade(t *testing.T) {
+	c := expiredParent(t)
+
+	removed, err := c.Expire("parent")
+	if !removed || !errors.Is(err, ErrCascadeFailed) {
+		t.Fatalf("got (%t, %v), want (true, ErrCascadeFailed)", removed, err)
+	}
+}
+
+func TestDeleteExpiredKeepsFailedEntries(t *testing.T) {
+	c := New[string, int](struct{}{})
+	for _, key := range []string{"a", "b", "c"} {
+		if err := c.CreateElementWithTTL(key, 1, time.Millisecond); err != nil {
+			t.Fatal(err)
+		}
+	}
+	c.OnExpire(func(_ *struct{}, entry Entry[string, int]) error {
+		if entry.Key == "b" {
+			return errors.New("expire refused")
+		}
+		return nil
+	})
+	time.Sleep(5 * time.Millisecond)
+
+	deleted, err := c.DeleteExpired()
+	if err == nil || deleted != 2 {
+		t.Fatalf("got (%d, %v), want 2 deleted and an error", deleted, err)
+	}
+	if c.Len() != 1 {
+		t.Fatalf("got %d entries, want 1", c.Len())
+	}
+
+	// The failed entry is still tracked, so it is retried
+	c.OnExpire(nil)
+	if deleted, err := c.DeleteExpired(); err != nil || deleted != 1 {
+		t.Fatalf("got (%d, %v), want 1 deleted", deleted, err)
+	}
+}
+
+func TestGetStaleKeepsIdleEntriesExpired(t *testing.T) {
+	c := New[string, int](struct{}{})
+	c.SetIdleTimeout(5 * time.Millisecond)
+	c.SetGracePeriod(time.Hour)
+	if err := c.CreateElement("a", 1); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(10 * time.Millisecond)
+
+	for range 2 {
+		value, stale, err := c.GetStale("a")
+		if err != nil || value != 1 || !stale {
+			t.Fatalf("got (%d, %t, %v), want (1, true, nil)", value, stale, err)
+		}
+	}
+	if _, err := c.GetElement("a"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("got error %v, want ErrNotFound", err)
+	}
+}
+
+func TestDeleteExpiredCascadesToDependents(t *testing.T) {
+	c := New[string, int](struct{}{})
+	if err := c.CreateElementWithTTL("parent", 1, time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"child", "other"} {
+		if err := c.CreateElement(key, 2); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := c.AddDependency("child", "parent"); err != nil {
+		t.Fatal(err)
+	}
+	var cascaded []string
+	c.OnCascade(func(_ *struct{}, _ string, entry Entry[string, int]) error {
+		cascaded = append(cascaded, entry.Key)
+		return nil
+	})
+	time.Sleep(5 * time.Millisecond)
+
+	deleted, err := c.DeleteExpired()
+	if err != nil || deleted != 1 {
+		t.Fatalf("got (%d, %v), want (1, nil)", deleted, err)
+	}
+	if len(cascaded) != 1 || cascaded[0] != "child" {
+		t.Fatalf("got cascaded %v, want [child]", cascaded)
+	}
+	if keys := c.Keys(); len(keys) != 1 || keys[0] != "other" {
+		t.Fatalf("got keys %v, want [other]", keys)
+	}
+}
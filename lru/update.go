@@ -0,0 +1,150 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lru
+
+import "time"
+
+// UpdateElement atomically replaces the value associated with the given key
+// with the one returned by fn, which receives the current value and whether it was found.
+// fn runs while the cache is locked, so read-modify-write cycles are race-free,
+// but it must be fast and must not call any other cache method.
+// When fn fails, the cache is left untouched and its error is returned.
+// The new value is inserted like CreateElement does, keeping the ttl and priority of the entry, and returned.
+func (c *LRU[K, V, MetaT]) UpdateElement(key K, fn func(old V, found bool) (V, error)) (V, error) {
+	if err := c.lockChecked(); err != nil {
+		var zero V
+		return zero, err
+	}
+	defer c.mu.Unlock()
+
+	old, found := c.peekUnsafe(key)
+	value, err := fn(old, found)
+	if err != nil {
+		return value, err
+	}
+	return value, c.createElementUnsafe(key, value, c.updateOptionsUnsafe(key))
+}
+
+// updateOptionsUnsafe returns the options the new value of the given key is inserted with,
+// without locking the LRU. Live entries keep their current ones, deadlines included, and
+// expired entries still stored start again with the same settings. Missing keys get the defaults.
+func (c *LRU[K, V, MetaT]) updateOptionsUnsafe(key K) *ElementOptions {
+	element, found := c.index[key]
+	if !found {
+		return nil
+	}
+
+	it := element.Value.(*item[K, V])
+	if !it.expired(time.Now()) {
+		return keepOptions
+	}
+	return &ElementOptions{TTL: it.ttl, Grace: it.grace, SoftTTL: it.softTTL, Priority: it.priority, Cost: it.fixedCost}
+}
+
+// peekUnsafe returns the value associated with the given key, and whether it was found,
+// without updating its recency nor locking the LRU
+func (c *LRU[K, V, MetaT]) peekUnsafe(key K) (V, bool) {
+	element, found := c.index[key]
+	if !found || element.Value.(*item[K, V]).expired(time.Now()) {
+		var zero V
+		return zero, false
+	}
+	return element.Value.(*item[K, V]).entry.Value, true
+}
+
+// Add inserts the entry only when the given key is not present yet,
+// and reports whether it was added. Existing values are never overwritten,
+// which makes Add suitable for idempotency tokens and deduplication.
+// Expired entries are considered missing.
+func (c *LRU[K, V, MetaT]) Add(key K, value V) (bool, error) {
+	if err := c.lockChecked(); err != nil {
+		return false, err
+	}
+	defer c.mu.Unlock()
+
+	if _, found := c.peekUnsafe(key); found {
+		return false, nil
+	}
+	if err := c.createElementUnsafe(key, value, c.updateOptionsUnsafe(key)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// CompareAndSwap replaces the value associated with the given key with new,
+// only when the current value is equal to old, and reports whether it was swapped.
+// Values are compared with ==, so ErrNotComparable is returned for values
+// that cannot be compared, like slices or maps.
+func (c *LRU[K, V, MetaT]) CompareAndSwap(key K, old, new V) (bool, error) {
+	if err := c.lockChecked(); err != nil {
+		return false, err
+	}
+	defer c.mu.Unlock()
+
+	current, found := c.peekUnsafe(key)
+	if !found {
+		return false, nil
+	}
+
+	equal, err := equalValues(current, old)
+	if !equal || err != nil {
+		return false, err
+	}
+	if err := c.createElementUnsafe(key, new, c.updateOptionsUnsafe(key)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// CompareAndDelete removes the entry associated with the given key, only when
+// its value is equal to old, and reports whether it was removed.
+// Values are compared like CompareAndSwap does.
+func (c *LRU[K, V, MetaT]) CompareAndDelete(key K, old V) (bool, error) {
+	if err := c.lockChecked(); err != nil {
+		return false, err
+	}
+	defer c.mu.Unlock()
+
+	if err := c.writableUnsafe(); err != nil {
+		return false, err
+	}
+
+	current, found := c.peekUnsafe(key)
+	if !found {
+		return false, nil
+	}
+
+	equal, err := equalValues(current, old)
+	if !equal || err != nil {
+		return false, err
+	}
+	if err := c.deleteElementUnsafe(key); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// equalValues compares two values with ==, returning ErrNotComparable instead of
+// panicking when their dynamic type does not support it
+func equalValues[V any](a, b V) (equal bool, err error) {
+	defer func() {
+		if recover() != nil {
+			equal, err = false, ErrNotComparable
+		}
+	}()
+	return any(a) == any(b), nil
+}
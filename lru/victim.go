@@ -0,0 +1,106 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lru
+
+import "container/list"
+
+// SelectVictim sets a handler that chooses which entry is evicted, replacing the
+// least recently used policy, so cost-aware or domain-specific policies can be built.
+// It receives up to `candidates` entries taken from the back of the list,
+// from the least recently used, and returns the position of the one to evict.
+// Returning a position out of range vetoes all of them: entries with higher priorities
+// are offered next, and the insertion fails when every candidate is vetoed.
+// Pinned entries, the ones whose key is locked, and the ones vetoed by CanEvict are never offered.
+// The second-chance mechanism does not apply while a handler is set.
+func (c *LRU[K, V, MetaT]) SelectVictim(candidates int, handler func(metadata *MetaT, candidates []Entry[K, V]) int) {
+	c.victimCandidates = max(candidates, 1)
+	c.selectVictimHandler = handler
+}
+
+// selectVictimUnsafe offers the evictable elements of the given priority to the SelectVictim handler,
+// without locking the LRU. It returns nil when there are none or all of them are vetoed.
+func (c *LRU[K, V, MetaT]) selectVictimUnsafe(priority int) *list.Element {
+	var elements []*list.Element
+	var candidates []Entry[K, V]
+
+	for element := c.list.Back(); element != nil && len(elements) < c.victimCandidates; element = element.Prev() {
+		if it := element.Value.(*item[K, V]); c.evictableUnsafe(it, priority) && c.canEvictUnsafe(it) {
+			elements = append(elements, element)
+			candidates = append(candidates, it.entry)
+		}
+	}
+	if len(elements) == 0 {
+		return nil
+	}
+
+	chosen := c.selectVictimHandler(&c.Metadata, candidates)
+	if chosen < 0 || chosen >= len(elements) {
+		return nil
+	}
+	return elements[chosen]
+}
+
+// CanEvict sets a handler consulted for each entry the eviction loop is about to evict,
+// or to offer to the SelectVictim handler. Returning false vetoes the eviction of that entry,
+// e.g. a file still being downloaded, and the loop moves on to the next candidate.
+// Unlike ShouldEvict, which decides whether to evict at all, it decides which entries may go.
+// The insertion fails with ErrEvictionStalled when every candidate is vetoed.
+func (c *LRU[K, V, MetaT]) CanEvict(handler func(metadata *MetaT, entry Entry[K, V]) bool) {
+	c.canEvictHandler = handler
+}
+
+// candidateUnsafe returns the least recently used evictable entry of the lowest priority,
+// or the zero Entry when there is none, without locking the LRU.
+// The CanEvict handler is not consulted, as nothing is evicted yet.
+func (c *LRU[K, V, MetaT]) candidateUnsafe() Entry[K, V] {
+	lowest := 0
+	for lowest = range c.priorityLevelsUnsafe() {
+		break
+	}
+
+	// A single scan keeps the least recently used evictable item of the lowest priority,
+	// stopping as soon as there cannot be a lower one
+	var candidate *item[K, V]
+	for element := c.list.Back(); element != nil; element = element.Prev() {
+		it := element.Value.(*item[K, V])
+		if candidate != nil && it.priority >= candidate.priority {
+			continue
+		}
+		if c.evictableUnsafe(it, it.priority) {
+			candidate = it
+			if candidate.priority == lowest {
+				break
+			}
+		}
+	}
+	if candidate == nil {
+		return Entry[K, V]{}
+	}
+	return candidate.entry
+}
+
+// evictableUnsafe reports whether an item of the given priority is neither pinned nor locked,
+// without locking the LRU
+func (c *LRU[K, V, MetaT]) evictableUnsafe(it *item[K, V], priority int) bool {
+	return it.priority == priority && !it.pinned && !c.keyLockedUnsafe(it.entry.Key)
+}
+
+// canEvictUnsafe consults the CanEvict handler, if any, about an item that is about
+// to be evicted, without locking the LRU
+func (c *LRU[K, V, MetaT]) canEvictUnsafe(it *item[K, V]) bool {
+	return c.canEvictHandler == nil || c.canEvictHandler(&c.Metadata, it.entry)
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lru
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// WarmConcurrently loads the given keys with at most `parallelism` loaders running at once,
+// and inserts the results into the cache. Keys already present are skipped.
+// Failures do not stop the warmup: every error is returned joined, annotated with its key.
+// When the context is done, no more keys are loaded and its error is returned too.
+func (c *LRU[K, V, MetaT]) WarmConcurrently(ctx context.Context, keys []K,
+	loader func(ctx context.Context, key K) (V, error), parallelism int) error {
+
+	parallelism = max(parallelism, 1)
+	pending := make(chan K)
+
+	var mu sync.Mutex
+	var errs []error
+	addError := func(err error) {
+		mu.Lock()
+		errs = append(errs, err)
+		mu.Unlock()
+	}
+
+	var wg sync.WaitGroup
+	for range parallelism {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for key := range pending {
+				if c.Contains(key) {
+					continue
+				}
+
+				value, err := loader(ctx, key)
+				if err == nil {
+					err = c.insertLocked(key, value, nil, OriginWarmup)
+				}
+				if err != nil {
+					addError(fmt.Errorf("cannot warm key %v: %w", key, err))
+				}
+			}
+		}()
+	}
+
+	// Feed the workers until every key is dispatched or the context is done
+dispatch:
+	for _, key := range keys {
+		select {
+		case <-ctx.Done():
+			addError(ctx.Err())
+			break dispatch
+		case pending <- key:
+		}
+	}
+	close(pending)
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lru
+
+import "time"
+
+// Lengths of the time-bucketed rollups: a bucket per minute during the last hour,
+// and a bucket per hour during the last day
+const (
+	minuteBuckets = 60
+	hourBuckets   = 24
+)
+
+// WindowStats are the statistics of the cache over a recent window of time.
+type WindowStats struct {
+	// Window is the period actually covered, the requested one rounded up to
+	// whole minutes, or to whole hours beyond the first one
+	Window time.Duration
+
+	Hits        uint64
+	Misses      uint64
+	Evictions   uint64
+	Expirations uint64
+}
+
+// HitRatio returns the ratio of lookups that found the key during the window
+func (s WindowStats) HitRatio() float64 {
+	return hitRatio(s.Hits, s.Misses)
+}
+
+// rollupBucket counts the operations of a single minute or hour
+type rollupBucket struct {
+	period      int64 // Minutes or hours since the epoch covered by the bucket
+	hits        uint64
+	misses      uint64
+	evictions   uint64
+	expirations uint64
+}
+
+// rollups keeps rings of per-minute and per-hour buckets. Buckets are reused
+// once their period is over, so no background goroutine is needed.
+type rollups struct {
+	minutes [minuteBuckets]rollupBucket
+	hours   [hourBuckets]rollupBucket
+}
+
+// record applies update to the current minute and hour buckets
+func (r *rollups) record(now time.Time, update func(b *rollupBucket)) {
+	update(bucketAt(r.minutes[:], now.Unix()/60))
+	update(bucketAt(r.hours[:], now.Unix()/3600))
+}
+
+// bucketAt returns the bucket of the ring covering the period, resetting it when
+// it still holds an older one
+func bucketAt(ring []rollupBucket, period int64) *rollupBucket {
+	bucket := &ring[period%int64(len(ring))]
+	if bucket.period != period {
+		*bucket = rollupBucket{period: period}
+	}
+	return bucket
+}
+
+// sumBuckets adds the buckets of the ring covering the last periods up to the given one
+func sumBuckets(ring []rollupBucket, period int64, periods int) WindowStats {
+	var stats WindowStats
+	for p := period - int64(periods) + 1; p <= period; p++ {
+		bucket := ring[p%int64(len(ring))]
+		if bucket.period != p {
+			continue
+		}
+		stats.Hits += bucket.hits
+		stats.Misses += bucket.misses
+		stats.Evictions += bucket.evictions
+		stats.Expirations += bucket.expirations
+	}
+	return stats
+}
+
+// WindowStats returns the statistics of the cache over the last window of time,
+// e.g. the hit ratio over the last 5 minutes or the evictions of the last hour.
+// Windows up to an hour are tracked per minute, and longer ones per hour, up to a day.
+// The current minute or hour is included, so the window covers a bit more than
+// asked for until it is over.
+func (c *LRU[K, V, MetaT]) WindowStats(window time.Duration) WindowStats {
+	c.rlock()
+	defer c.mu.RUnlock()
+
+	now := time.Now().Unix()
+	if window <= time.Hour {
+		minutes := min(max(int((window+time.Minute-1)/time.Minute), 1), minuteBuckets)
+		stats := sumBuckets(c.rollups.minutes[:], now/60, minutes)
+		stats.Window = time.Duration(minutes) * time.Minute
+		return stats
+	}
+
+	hours := min(int((window+time.Hour-1)/time.Hour), hourBuckets)
+	stats := sumBuckets(c.rollups.hours[:], now/3600, hours)
+	stats.Window = time.Duration(hours) * time.Hour
+	return stats
+}
+
+// recordLookupUnsafe counts a lookup as a hit or a miss without locking the LRU
+func (c *LRU[K, V, MetaT]) recordLookupUnsafe(found bool) {
+	if found {
+		c.counters.hits.Add(1)
+		c.rollups.record(time.Now(), func(b *rollupBucket) { b.hits++ })
+		return
+	}
+	c.counters.misses.Add(1)
+	c.rollups.record(time.Now(), func(b *rollupBucket) { b.misses++ })
+}